@@ -0,0 +1,20 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// AccountAPI define the private APIs to query user's information, orders,
+// trades, and transactions, including withdrawal.
+//
+// This interface is implemented by Client, WebSocketPrivate, and
+// FailoverClient.
+type AccountAPI interface {
+	UserInfo() (*User, error)
+	UserOrderInfo(pair string, id int64) (*Trade, error)
+	UserOrdersClosed(pair string, timeAfter, timeBefore int64) ([]Trade, error)
+	UserOrdersOpen(pair string) (PairTradesOpen, error)
+	UserTrades(tp ListTradeParams) ([]Trade, error)
+	UserTransactions(asset string, limit int64) (*AssetTransactions, error)
+	UserWithdraw(wreq *WithdrawRequest) (*WithdrawItem, error)
+}
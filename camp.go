@@ -198,6 +198,10 @@ const (
 // DefaultLimit define maximum number of record fetched per request.
 const DefaultLimit = 100
 
+// DefaultTradeBulkMaxItems define the maximum number of orders and
+// cancellation send on single TradeBulk request by ExecuteTradeBulk.
+const DefaultTradeBulkMaxItems = 20
+
 // List of valid sort values.
 const (
 	SortAscending  = "asc"
@@ -236,6 +240,11 @@ var (
 		Message: `invalid sort-by parameter, its either "asc" or "desc"`,
 		Name:    "ERR_INVALID_SORT_BY",
 	}
+	ErrInvalidTopic = &errors.E{
+		Code:    http.StatusBadRequest,
+		Message: "invalid or unknown topic",
+		Name:    "ERR_INVALID_TOPIC",
+	}
 	ErrInvalidTradeID = &errors.E{
 		Code:    http.StatusBadRequest,
 		Message: "invalid trade ID",
@@ -273,6 +282,38 @@ var (
 		Message: "not enough amount in the market to process fill-or-kill order",
 		Name:    "ERR_TRADE_FILL_OR_KILL",
 	}
+	ErrTradeBulkNotProcessed = &liberrors.E{
+		Code:    http.StatusServiceUnavailable,
+		Message: "the bulk order item is not processed by server",
+		Name:    "ERR_TRADE_BULK_NOT_PROCESSED",
+	}
+	ErrTradeBulkUnknown = &liberrors.E{
+		Code:    http.StatusServiceUnavailable,
+		Message: "the bulk order item may have been processed by server, check the order status before retrying",
+		Name:    "ERR_TRADE_BULK_UNKNOWN",
+	}
+
+	ErrOrderBookCrossed = &liberrors.E{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "the best bid price is greater or equal to the best ask price",
+		Name:    "ERR_ORDER_BOOK_CROSSED",
+	}
+	ErrOrderBookInvalidDepth = &liberrors.E{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "the depth has empty or non-positive price, or negative amount",
+		Name:    "ERR_ORDER_BOOK_INVALID_DEPTH",
+	}
+
+	ErrWebSocketDisconnected = &liberrors.E{
+		Code:    http.StatusServiceUnavailable,
+		Message: "the WebSocket connection lost before receiving response",
+		Name:    "ERR_WEBSOCKET_DISCONNECTED",
+	}
+	ErrWebSocketTimeout = &liberrors.E{
+		Code:    http.StatusGatewayTimeout,
+		Message: "timeout waiting for WebSocket response",
+		Name:    "ERR_WEBSOCKET_TIMEOUT",
+	}
 
 	ErrWalletAddress = &errors.E{
 		Code:    http.StatusBadRequest,
@@ -0,0 +1,46 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"net/http"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+)
+
+// List of default credential used by Server.
+const (
+	DefaultToken  = "camptest-token"
+	DefaultSecret = "camptest-secret"
+)
+
+// List of errors returned by Server.
+var (
+	ErrUnauthorized = &liberrors.E{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid or empty Key or Sign header",
+		Name:    "ERR_UNAUTHORIZED",
+	}
+	ErrNotFound = &liberrors.E{
+		Code:    http.StatusNotFound,
+		Message: "endpoint not found",
+		Name:    "ERR_NOT_FOUND",
+	}
+	ErrOrderNotFound = &liberrors.E{
+		Code:    http.StatusNotFound,
+		Message: "order not found",
+		Name:    "ERR_ORDER_NOT_FOUND",
+	}
+	ErrInvalidJSON = &liberrors.E{
+		Code:    http.StatusBadRequest,
+		Message: "invalid JSON request body",
+		Name:    "ERR_INVALID_JSON",
+	}
+	ErrInternal = &liberrors.E{
+		Code:    http.StatusInternalServerError,
+		Message: "internal server error",
+		Name:    "ERR_INTERNAL",
+	}
+)
@@ -0,0 +1,30 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package camptest provide the in-process mock of CAMP API v2 server, for
+// testing the application built on top of camp.Client without live
+// credential.
+//
+// The Server implement all REST API v2 endpoints, verify the Key and Sign
+// headers on private endpoints using the same camp.Sign algorithm, and
+// return the response in the same envelope as the live server.
+//
+// The market data are set by the test using the Set methods, and the user
+// orders are kept in memory: the limit order is stored as open order until
+// its cancelled, and the market order is filled immediately.
+// There is no matching engine and the user balances are not changed by the
+// orders.
+//
+// The response of each endpoint can be scripted using Server.Handle and
+// Server.HandleOnce, for example to return an error.
+//
+// The same Server also serve the WebSocket endpoints camp.WSPublic and
+// camp.WSPrivate, so camp.WebSocketPublic and camp.WebSocketPrivate can
+// connect to it using the Env.
+// The WebSocket request is handled by the same handler as REST API, and
+// the subscription on camp.WSPublicSubscription is kept per connection.
+// The test can push the broadcast messages using the Push methods, drop
+// the connections using Server.DropWebSocket to test the reconnect, or
+// delay the responses using Server.SetWebSocketDelay.
+package camptest
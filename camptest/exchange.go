@@ -0,0 +1,685 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+
+	"github.com/campinvestment/camp-go"
+)
+
+// exchangeRoute define the default handler for each endpoint.
+type exchangeRoute func(ex *exchange, req *Request) (interface{}, error)
+
+// exchangeRoutes map the method and path into its default handler.
+var exchangeRoutes = map[string]exchangeRoute{
+	routeKey(http.MethodGet, camp.APIMarketDepths):     (*exchange).marketDepths,
+	routeKey(http.MethodGet, camp.APIMarketInfo):       (*exchange).marketInfo,
+	routeKey(http.MethodGet, camp.APIMarketPrices):     (*exchange).marketPrices,
+	routeKey(http.MethodGet, camp.APIMarketSummaries):  (*exchange).marketSummaries,
+	routeKey(http.MethodGet, camp.APIMarketTicker):     (*exchange).marketTicker,
+	routeKey(http.MethodGet, camp.APIMarketTrades):     (*exchange).marketTrades,
+	routeKey(http.MethodGet, camp.APIMarketTradesOpen): (*exchange).marketTradesOpen,
+
+	routeKey(http.MethodGet, camp.APIUserInfo):          (*exchange).userInfo,
+	routeKey(http.MethodGet, camp.APIUserOrderInfo):     (*exchange).userOrderInfo,
+	routeKey(http.MethodGet, camp.APIUserOrdersClosed):  (*exchange).userOrdersClosed,
+	routeKey(http.MethodGet, camp.APIUserOrdersOpen):    (*exchange).userOrdersOpen,
+	routeKey(http.MethodGet, camp.APIUserTrades):        (*exchange).userTrades,
+	routeKey(http.MethodGet, camp.APIUserTransactions):  (*exchange).userTransactions,
+	routeKey(http.MethodPost, camp.APIUserWithdraw):     (*exchange).userWithdraw,
+	routeKey(http.MethodPost, camp.APITradeAsk):         (*exchange).tradeAsk,
+	routeKey(http.MethodPost, camp.APITradeBid):         (*exchange).tradeBid,
+	routeKey(http.MethodPost, camp.APITradeBulk):        (*exchange).tradeBulk,
+	routeKey(http.MethodDelete, camp.APITradeCancelAll): (*exchange).tradeCancelAll,
+	routeKey(http.MethodDelete, camp.APITradeCancelAsk): (*exchange).tradeCancelAsk,
+	routeKey(http.MethodDelete, camp.APITradeCancelBid): (*exchange).tradeCancelBid,
+}
+
+// exchange contains the in-memory state of market and user.
+type exchange struct {
+	depths     map[string]*camp.MarketDepths
+	tickers    map[string]*camp.MarketTicker
+	trades     map[string]*camp.MarketTrades
+	tradesOpen map[string]*camp.TradesOpen
+	prices     camp.MarketPrices
+	summaries  *camp.MarketSummaries
+	user       *camp.User
+	trans      *camp.AssetTransactions
+
+	// orders contains the user orders, open and closed, by ID.
+	// The open order has empty Status.
+	orders map[int64]*camp.Trade
+
+	infos []camp.MarketInfo
+
+	lastID int64
+
+	locker sync.Mutex
+}
+
+func newExchange() (ex *exchange) {
+	ex = &exchange{
+		depths:     make(map[string]*camp.MarketDepths),
+		tickers:    make(map[string]*camp.MarketTicker),
+		trades:     make(map[string]*camp.MarketTrades),
+		tradesOpen: make(map[string]*camp.TradesOpen),
+		prices:     make(camp.MarketPrices),
+		summaries:  &camp.MarketSummaries{},
+		user:       newUser(),
+		trans:      newAssetTransactions(),
+		orders:     make(map[int64]*camp.Trade),
+		infos: []camp.MarketInfo{
+			newMarketInfo(camp.AssetNameBitcoin, camp.AssetNameTether),
+			newMarketInfo(camp.AssetNameEthereum, camp.AssetNameTether),
+		},
+	}
+	return ex
+}
+
+// newAssetTransactions create the default, empty, deposit and withdraw
+// history.
+func newAssetTransactions() *camp.AssetTransactions {
+	return &camp.AssetTransactions{
+		Deposit:  make(map[string][]camp.DepositItem),
+		Withdraw: make(map[string][]camp.WithdrawItem),
+	}
+}
+
+// newUser create the default user with ID 1 and empty assets.
+func newUser() *camp.User {
+	return &camp.User{
+		UserAssets: camp.NewUserAssets(),
+		Wallets:    make(camp.UserWallets),
+		Email:      "camptest@example.com",
+		FullName:   "CAMP Test",
+		ID:         1,
+	}
+}
+
+// newMarketInfo create active market for pair coin_base.
+func newMarketInfo(coin, base string) camp.MarketInfo {
+	pair := coin + "_" + base
+	return camp.MarketInfo{
+		PriceMinimum:    big.NewRat("0.00000001"),
+		AmountMinimum:   big.NewRat("0.00000001"),
+		ID:              pair,
+		Symbol:          pair,
+		Pair:            pair,
+		CoinAsset:       coin,
+		BaseAsset:       base,
+		PricePrecision:  8,
+		AmountPrecision: 8,
+		IsActive:        true,
+	}
+}
+
+// routeKey return the key of route from HTTP method and path.
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// call the default handler of request.
+// The caller must hold the lock.
+func (ex *exchange) call(req *Request) (data interface{}, err error) {
+	route, ok := exchangeRoutes[routeKey(req.Method, req.Path)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return route(ex, req)
+}
+
+// addOrder store the copy of trade as user order.
+// If the trade ID is zero, it will set to new ID.
+func (ex *exchange) addOrder(trade camp.Trade) int64 {
+	if trade.ID <= 0 {
+		ex.lastID++
+		trade.ID = ex.lastID
+	} else if trade.ID > ex.lastID {
+		ex.lastID = trade.ID
+	}
+	ex.orders[trade.ID] = &trade
+	return trade.ID
+}
+
+// checkPair return camp.ErrInvalidPair if the pair is empty or not listed
+// in market info.
+func (ex *exchange) checkPair(pairName string) error {
+	if len(pairName) == 0 {
+		return camp.ErrInvalidPair
+	}
+	for _, info := range ex.infos {
+		if info.Pair == pairName {
+			return nil
+		}
+	}
+	return camp.ErrInvalidPair
+}
+
+// listOrders return the copy of user orders that match with the filter,
+// sorted by ID in ascending order.
+func (ex *exchange) listOrders(filter func(order *camp.Trade) bool) (
+	list []camp.Trade,
+) {
+	list = make([]camp.Trade, 0)
+	for _, order := range ex.orders {
+		if filter(order) {
+			list = append(list, *order)
+		}
+	}
+	sort.Slice(list, func(x, y int) bool {
+		return list[x].ID < list[y].ID
+	})
+	return list
+}
+
+func (ex *exchange) marketDepths(req *Request) (interface{}, error) {
+	pair := req.Params.Get(camp.ParamNamePair)
+	err := ex.checkPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	depths := ex.depths[pair]
+	if depths == nil {
+		depths = &camp.MarketDepths{
+			Pair: pair,
+			Asks: []*camp.Depth{},
+			Bids: []*camp.Depth{},
+		}
+	}
+	return depths, nil
+}
+
+func (ex *exchange) marketInfo(req *Request) (interface{}, error) {
+	return ex.infos, nil
+}
+
+func (ex *exchange) marketPrices(req *Request) (interface{}, error) {
+	return ex.prices, nil
+}
+
+func (ex *exchange) marketSummaries(req *Request) (interface{}, error) {
+	return ex.summaries, nil
+}
+
+func (ex *exchange) marketTicker(req *Request) (interface{}, error) {
+	pair := req.Params.Get(camp.ParamNamePair)
+	err := ex.checkPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	tick := ex.tickers[pair]
+	if tick == nil {
+		tick = &camp.MarketTicker{
+			PairName: pair,
+		}
+	}
+	return tick, nil
+}
+
+func (ex *exchange) marketTrades(req *Request) (interface{}, error) {
+	pair := req.Params.Get(camp.ParamNamePair)
+	err := ex.checkPair(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		offset = paramInt(req.Params, camp.ParamNameOffset)
+		limit  = paramInt(req.Params, camp.ParamNameLimit)
+		res    = &camp.MarketTrades{
+			Asks: []camp.Trade{},
+			Bids: []camp.Trade{},
+		}
+	)
+
+	trades := ex.trades[pair]
+	if trades != nil {
+		res.Asks = pageTrades(trades.Asks, offset, limit)
+		res.Bids = pageTrades(trades.Bids, offset, limit)
+	}
+	return res, nil
+}
+
+func (ex *exchange) marketTradesOpen(req *Request) (interface{}, error) {
+	pair := req.Params.Get(camp.ParamNamePair)
+	err := ex.checkPair(pair)
+	if err != nil {
+		return nil, err
+	}
+	open := ex.tradesOpen[pair]
+	if open == nil {
+		open = &camp.TradesOpen{
+			Asks: []camp.Trade{},
+			Bids: []camp.Trade{},
+		}
+	}
+	return open, nil
+}
+
+func (ex *exchange) userInfo(req *Request) (interface{}, error) {
+	return ex.user, nil
+}
+
+func (ex *exchange) userOrderInfo(req *Request) (interface{}, error) {
+	var (
+		pair = req.Params.Get(camp.ParamNamePair)
+		id   = paramInt(req.Params, camp.ParamNameTradeID)
+	)
+	if id <= 0 {
+		return nil, camp.ErrInvalidTradeID
+	}
+	order := ex.orders[id]
+	if order == nil || order.Pair != pair {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (ex *exchange) userOrdersClosed(req *Request) (interface{}, error) {
+	pair := req.Params.Get(camp.ParamNamePair)
+	list := ex.listOrders(func(order *camp.Trade) bool {
+		return len(order.Status) != 0 &&
+			(len(pair) == 0 || order.Pair == pair)
+	})
+	// The latest closed order first.
+	sort.Slice(list, func(x, y int) bool {
+		return list[x].ID > list[y].ID
+	})
+	return list, nil
+}
+
+func (ex *exchange) userOrdersOpen(req *Request) (interface{}, error) {
+	var (
+		pair  = req.Params.Get(camp.ParamNamePair)
+		pairs = make(camp.PairTradesOpen)
+	)
+
+	list := ex.listOrders(func(order *camp.Trade) bool {
+		return len(order.Status) == 0 &&
+			(len(pair) == 0 || order.Pair == pair)
+	})
+	for _, order := range list {
+		open := pairs[order.Pair]
+		if order.Type == camp.TradeTypeAsk {
+			open.Asks = append(open.Asks, order)
+		} else {
+			open.Bids = append(open.Bids, order)
+		}
+		pairs[order.Pair] = open
+	}
+	return pairs, nil
+}
+
+func (ex *exchange) userTrades(req *Request) (interface{}, error) {
+	var (
+		pair       = req.Params.Get(camp.ParamNamePair)
+		sortBy     = req.Params.Get(camp.ParamNameSort)
+		idAfter    = paramInt(req.Params, camp.ParamNameIDAfter)
+		idBefore   = paramInt(req.Params, camp.ParamNameIDBefore)
+		timeAfter  = paramInt(req.Params, camp.ParamNameTimeAfter)
+		timeBefore = paramInt(req.Params, camp.ParamNameTimeBefore)
+		offset     = paramInt(req.Params, camp.ParamNameOffset)
+		limit      = paramInt(req.Params, camp.ParamNameLimit)
+	)
+
+	switch sortBy {
+	case "", camp.SortDescending, camp.SortAscending:
+	default:
+		return nil, camp.ErrInvalidSortBy
+	}
+
+	list := ex.listOrders(func(order *camp.Trade) bool {
+		switch {
+		case order.Status != camp.TradeStatusFilled:
+		case len(pair) != 0 && order.Pair != pair:
+		case idAfter > 0 && order.ID < idAfter:
+		case idBefore > 0 && order.ID > idBefore:
+		case timeAfter > 0 && order.FinishTime < timeAfter:
+		case timeBefore > 0 && order.FinishTime > timeBefore:
+		default:
+			return true
+		}
+		return false
+	})
+	if sortBy != camp.SortAscending {
+		sort.Slice(list, func(x, y int) bool {
+			return list[x].ID > list[y].ID
+		})
+	}
+	return pageTrades(list, offset, limit), nil
+}
+
+func (ex *exchange) userTransactions(req *Request) (interface{}, error) {
+	var (
+		asset = req.Params.Get(camp.ParamNameAsset)
+		limit = paramInt(req.Params, camp.ParamNameLimit)
+		trans = &camp.AssetTransactions{
+			Deposit:  make(map[string][]camp.DepositItem),
+			Withdraw: make(map[string][]camp.WithdrawItem),
+		}
+	)
+	if limit <= 0 || limit > camp.DefaultLimit {
+		limit = camp.DefaultLimit
+	}
+
+	for name, list := range ex.trans.Deposit {
+		if len(asset) != 0 && name != asset {
+			continue
+		}
+		if int64(len(list)) > limit {
+			list = list[:limit]
+		}
+		trans.Deposit[name] = list
+	}
+	for name, list := range ex.trans.Withdraw {
+		if len(asset) != 0 && name != asset {
+			continue
+		}
+		if int64(len(list)) > limit {
+			list = list[:limit]
+		}
+		trans.Withdraw[name] = list
+	}
+	return trans, nil
+}
+
+func (ex *exchange) userWithdraw(req *Request) (interface{}, error) {
+	wreq := &camp.WithdrawRequest{
+		Amount:      big.NewRat(req.Params.Get(camp.ParamNameAmount)),
+		RequestID:   req.Params.Get(camp.ParamNameRequestID),
+		Asset:       req.Params.Get(camp.ParamNameAsset),
+		Network:     req.Params.Get(camp.ParamNameNetwork),
+		Address:     req.Params.Get(camp.ParamNameAddress),
+		AddressType: req.Params.Get(camp.ParamNameAddressType),
+		Memo:        req.Params.Get(camp.ParamNameMemo),
+	}
+	_, _, err := wreq.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	ex.lastID++
+
+	withdraw := camp.WithdrawItem{
+		Amount:      wreq.Amount,
+		Fee:         big.NewRat(0),
+		FinalAmount: wreq.Amount,
+		RequestID:   wreq.RequestID,
+		Asset:       wreq.Asset,
+		Network:     wreq.Network,
+		Status:      "pending",
+		Address:     wreq.Address,
+		AddressType: wreq.AddressType,
+		Memo:        wreq.Memo,
+		ID:          ex.lastID,
+		SubmitTime:  time.Now().Unix(),
+	}
+	ex.trans.Withdraw[wreq.Asset] = append(ex.trans.Withdraw[wreq.Asset],
+		withdraw)
+
+	return &withdraw, nil
+}
+
+func (ex *exchange) tradeAsk(req *Request) (interface{}, error) {
+	return ex.trade(req, camp.TradeTypeAsk)
+}
+
+func (ex *exchange) tradeBid(req *Request) (interface{}, error) {
+	return ex.trade(req, camp.TradeTypeBid)
+}
+
+func (ex *exchange) trade(req *Request, tradeType string) (interface{}, error) {
+	treq := &camp.TradeRequest{
+		Price:      big.NewRat(req.Params.Get(camp.ParamNamePrice)),
+		Amount:     big.NewRat(req.Params.Get(camp.ParamNameAmount)),
+		Type:       tradeType,
+		Method:     req.Params.Get(camp.ParamNameTradeMethod),
+		Pair:       req.Params.Get(camp.ParamNamePair),
+		IsPostOnly: req.Params.Get(camp.ParamNamePostOnly) == "true",
+	}
+
+	order, err := ex.placeOrder(treq)
+	if err != nil {
+		return nil, err
+	}
+
+	tres := &camp.TradeResponse{
+		Order: order,
+		User:  *ex.user,
+	}
+	return tres, nil
+}
+
+func (ex *exchange) tradeBulk(req *Request) (interface{}, error) {
+	tbReq := &camp.TradeBulk{}
+	err := json.Unmarshal(req.Body, tbReq)
+	if err != nil {
+		return nil, ErrInvalidJSON
+	}
+
+	tbRes := &camp.TradeBulk{
+		Pair:      tbReq.Pair,
+		Orders:    make([]*camp.BulkOrderItem, 0, len(tbReq.Orders)),
+		Cancel:    make([]*camp.BulkOrderItem, 0, len(tbReq.Cancel)),
+		Timestamp: time.Now().Unix(),
+	}
+
+	for _, item := range tbReq.Orders {
+		resItem := &camp.BulkOrderItem{
+			TradeRequest: item.TradeRequest,
+			RefID:        item.RefID,
+		}
+		if len(resItem.Pair) == 0 {
+			resItem.Pair = tbReq.Pair
+		}
+
+		order, err := ex.placeOrder(&resItem.TradeRequest)
+		if err != nil {
+			resItem.E = *toErrorE(err)
+		} else {
+			resItem.Code = http.StatusOK
+			resItem.ID = order.ID
+		}
+		tbRes.Orders = append(tbRes.Orders, resItem)
+	}
+
+	for _, item := range tbReq.Cancel {
+		resItem := &camp.BulkOrderItem{
+			TradeRequest: item.TradeRequest,
+			ID:           item.ID,
+			RefID:        item.RefID,
+		}
+		if len(resItem.Pair) == 0 {
+			resItem.Pair = tbReq.Pair
+		}
+
+		_, err = ex.cancelOrder(resItem.Pair, resItem.Type, item.ID)
+		if err != nil {
+			resItem.E = *toErrorE(err)
+		} else {
+			resItem.Code = http.StatusOK
+		}
+		tbRes.Cancel = append(tbRes.Cancel, resItem)
+	}
+
+	return tbRes, nil
+}
+
+func (ex *exchange) tradeCancelAll(req *Request) (interface{}, error) {
+	var (
+		now       = time.Now().Unix()
+		cancelled = make([]camp.Trade, 0)
+	)
+	for _, order := range ex.listOrders(isOpenOrder) {
+		open := ex.orders[order.ID]
+		open.Status = camp.TradeStatusCancelled
+		open.FinishTime = now
+		cancelled = append(cancelled, *open)
+	}
+	return cancelled, nil
+}
+
+func (ex *exchange) tradeCancelAsk(req *Request) (interface{}, error) {
+	return ex.cancel(req, camp.TradeTypeAsk)
+}
+
+func (ex *exchange) tradeCancelBid(req *Request) (interface{}, error) {
+	return ex.cancel(req, camp.TradeTypeBid)
+}
+
+func (ex *exchange) cancel(req *Request, tradeType string) (interface{}, error) {
+	var (
+		pair = req.Params.Get(camp.ParamNamePair)
+		id   = paramInt(req.Params, camp.ParamNameTradeID)
+	)
+
+	order, err := ex.cancelOrder(pair, tradeType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tres := &camp.TradeResponse{
+		Order: order,
+		User:  *ex.user,
+	}
+	return tres, nil
+}
+
+// cancelOrder cancel the open order by pair and ID.
+// If the tradeType is not empty, the order type must match with it.
+func (ex *exchange) cancelOrder(pair, tradeType string, id int64) (
+	order *camp.Trade, err error,
+) {
+	if id <= 0 {
+		return nil, camp.ErrInvalidTradeID
+	}
+
+	open := ex.orders[id]
+	switch {
+	case open == nil, !isOpenOrder(open), open.Pair != pair:
+		return nil, ErrOrderNotFound
+	case len(tradeType) != 0 && open.Type != tradeType:
+		return nil, ErrOrderNotFound
+	}
+
+	open.Status = camp.TradeStatusCancelled
+	open.FinishTime = time.Now().Unix()
+
+	order = &camp.Trade{}
+	*order = *open
+	return order, nil
+}
+
+// placeOrder validate the trade request and store it as new order.
+// The limit order is kept open and the market order is filled immediately
+// using the last price in ticker, if its exist.
+func (ex *exchange) placeOrder(treq *camp.TradeRequest) (
+	order *camp.Trade, err error,
+) {
+	err = ex.checkPair(treq.Pair)
+	if err != nil {
+		return nil, err
+	}
+	switch treq.Type {
+	case camp.TradeTypeAsk, camp.TradeTypeBid:
+	default:
+		return nil, camp.ErrInvalidTradeType
+	}
+	_, _, err = treq.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		now        = time.Now().Unix()
+		coin, base = splitPair(treq.Pair)
+		amount     = big.NewRat(treq.Amount)
+		price      *big.Rat
+		tick       = ex.tickers[treq.Pair]
+		isMarket   = treq.Method == camp.TradeMethodMarket
+	)
+
+	if isMarket {
+		if tick != nil && tick.LastPrice != nil {
+			price = big.NewRat(tick.LastPrice)
+		}
+	} else {
+		price = big.NewRat(treq.Price)
+	}
+
+	order = &camp.Trade{
+		Price:      price,
+		CoinAmount: amount,
+		CoinFilled: big.NewRat(0),
+		CoinRemain: big.NewRat(amount),
+		Pair:       treq.Pair,
+		Type:       treq.Type,
+		Method:     treq.Method,
+		BaseAsset:  base,
+		CoinAsset:  coin,
+		SubmitTime: now,
+	}
+	if price != nil {
+		order.BaseAmount = big.MulRat(price, amount)
+		order.BaseFilled = big.NewRat(0)
+		order.BaseRemain = big.NewRat(order.BaseAmount)
+	}
+	if isMarket {
+		order.CoinFilled = big.NewRat(amount)
+		order.CoinRemain = big.NewRat(0)
+		order.BaseFilled = big.NewRat(order.BaseAmount)
+		order.BaseRemain = nil
+		order.Status = camp.TradeStatusFilled
+		order.FinishTime = now
+	}
+
+	order.ID = ex.addOrder(*order)
+
+	return order, nil
+}
+
+// isOpenOrder return true if the order status is empty.
+func isOpenOrder(order *camp.Trade) bool {
+	return len(order.Status) == 0
+}
+
+// pageTrades return the trades from offset until limit.
+// If limit is less or equal to zero, it will set to camp.DefaultLimit.
+func pageTrades(trades []camp.Trade, offset, limit int64) []camp.Trade {
+	if limit <= 0 || limit > camp.DefaultLimit {
+		limit = camp.DefaultLimit
+	}
+	if offset < 0 || offset >= int64(len(trades)) {
+		return []camp.Trade{}
+	}
+	end := offset + limit
+	if end > int64(len(trades)) {
+		end = int64(len(trades))
+	}
+	return trades[offset:end]
+}
+
+// paramInt return the value of parameter as int64, or 0 if its empty or
+// invalid.
+func paramInt(params url.Values, name string) int64 {
+	v, _ := strconv.ParseInt(params.Get(name), 10, 64)
+	return v
+}
+
+// splitPair split the pair name into coin and base asset names.
+func splitPair(pairName string) (coin, base string) {
+	coin, base, _ = strings.Cut(pairName, "_")
+	return coin, base
+}
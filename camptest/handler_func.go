@@ -0,0 +1,12 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+// HandlerFunc define the function to script the response of endpoint.
+//
+// The returned data is send as "data" in response envelope.
+// If the err is *liberrors.E, its Code, Message, and Name are send as
+// response, otherwise the response is ErrInternal.
+type HandlerFunc func(req *Request) (data interface{}, err error)
@@ -0,0 +1,28 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Request contains the request received by Server.
+type Request struct {
+	// Header of HTTP request.
+	Header http.Header
+
+	// Params contains the query parameters and the form body.
+	Params url.Values
+
+	// Method and Path of request, for example "GET" and
+	// camp.APIMarketDepths.
+	Method string
+	Path   string
+
+	// Body contains the raw request body, for example the JSON of
+	// camp.TradeBulk.
+	Body []byte
+}
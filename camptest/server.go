@@ -0,0 +1,413 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+
+	"github.com/campinvestment/camp-go"
+)
+
+// Server is the mock of CAMP REST API v2 server, running on local address
+// using httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	ex *exchange
+
+	// hooks and hooksOnce contains the scripted handlers by method and
+	// path.
+	hooks     map[string]HandlerFunc
+	hooksOnce map[string][]HandlerFunc
+
+	requests []*Request
+
+	// wsConns contains the active WebSocket connections.
+	wsConns map[*wsConn]struct{}
+
+	// wsDelay define the delay before responding each WebSocket
+	// request.
+	wsDelay time.Duration
+
+	// Token and Secret define the credential that accepted by server on
+	// private endpoints.
+	// Its should not be changed after the server started.
+	Token  string
+	Secret string
+
+	locker sync.Mutex
+}
+
+// NewServer create and start new mock server that accept the token and
+// secret as credential.
+// If token or secret is empty, it will set to DefaultToken or
+// DefaultSecret.
+// The server must be closed by calling Close.
+func NewServer(token, secret string) (srv *Server) {
+	if len(token) == 0 {
+		token = DefaultToken
+	}
+	if len(secret) == 0 {
+		secret = DefaultSecret
+	}
+
+	srv = &Server{
+		ex:        newExchange(),
+		hooks:     make(map[string]HandlerFunc),
+		hooksOnce: make(map[string][]HandlerFunc),
+		wsConns:   make(map[*wsConn]struct{}),
+		Token:     token,
+		Secret:    secret,
+	}
+	srv.Server = httptest.NewServer(srv)
+	return srv
+}
+
+// Close drop all WebSocket connections and shutdown the server.
+func (srv *Server) Close() {
+	srv.DropWebSocket("")
+	srv.Server.Close()
+}
+
+// Env return new camp.Environment to connect to the server, using the
+// server credential.
+func (srv *Server) Env() *camp.Environment {
+	return &camp.Environment{
+		Address: srv.URL,
+		Token:   srv.Token,
+		Secret:  srv.Secret,
+	}
+}
+
+// Handle set the handler for request with method and path, replacing the
+// default one.
+// The private endpoint still verify the credential before calling the
+// handler.
+// Set the handler to nil to restore the default handler.
+func (srv *Server) Handle(method, path string, handler HandlerFunc) {
+	key := routeKey(method, path)
+
+	srv.locker.Lock()
+	if handler == nil {
+		delete(srv.hooks, key)
+	} else {
+		srv.hooks[key] = handler
+	}
+	srv.locker.Unlock()
+}
+
+// HandleOnce queue the handler for the next request with method and path.
+// The queued handlers are called in order, one per request, before the
+// handler set by Handle or the default one.
+func (srv *Server) HandleOnce(method, path string, handler HandlerFunc) {
+	key := routeKey(method, path)
+
+	srv.locker.Lock()
+	srv.hooksOnce[key] = append(srv.hooksOnce[key], handler)
+	srv.locker.Unlock()
+}
+
+// Requests return all requests received by server, in order.
+func (srv *Server) Requests() []*Request {
+	srv.locker.Lock()
+	defer srv.locker.Unlock()
+	return append([]*Request(nil), srv.requests...)
+}
+
+// AddOrder add the trade as user order.
+// If the trade Status is empty the order is open, otherwise its closed.
+// If the trade ID is zero, it will be set to new ID.
+// It will return the order ID.
+func (srv *Server) AddOrder(trade camp.Trade) int64 {
+	srv.ex.locker.Lock()
+	defer srv.ex.locker.Unlock()
+	return srv.ex.addOrder(trade)
+}
+
+// Orders return the user orders, open and closed, sorted by ID.
+func (srv *Server) Orders() []camp.Trade {
+	srv.ex.locker.Lock()
+	defer srv.ex.locker.Unlock()
+	return srv.ex.listOrders(func(*camp.Trade) bool {
+		return true
+	})
+}
+
+// SetMarketDepths set the response of camp.APIMarketDepths for the pair
+// in depths.
+func (srv *Server) SetMarketDepths(depths *camp.MarketDepths) {
+	srv.ex.locker.Lock()
+	srv.ex.depths[depths.Pair] = depths
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketInfo set the list of markets.
+// Only the pair in the list are accepted by server.
+func (srv *Server) SetMarketInfo(infos []camp.MarketInfo) {
+	srv.ex.locker.Lock()
+	srv.ex.infos = infos
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketPrices set the response of camp.APIMarketPrices.
+func (srv *Server) SetMarketPrices(prices camp.MarketPrices) {
+	srv.ex.locker.Lock()
+	srv.ex.prices = prices
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketSummaries set the response of camp.APIMarketSummaries.
+func (srv *Server) SetMarketSummaries(summaries *camp.MarketSummaries) {
+	srv.ex.locker.Lock()
+	srv.ex.summaries = summaries
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketTicker set the response of camp.APIMarketTicker for the pair in
+// tick.
+// The ticker last price is used as the price of market order.
+func (srv *Server) SetMarketTicker(tick *camp.MarketTicker) {
+	srv.ex.locker.Lock()
+	srv.ex.tickers[tick.PairName] = tick
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketTrades set the completed trades for pair, the response of
+// camp.APIMarketTrades.
+// The Asks and Bids are paged using the offset and limit parameters.
+func (srv *Server) SetMarketTrades(pairName string, trades *camp.MarketTrades) {
+	srv.ex.locker.Lock()
+	srv.ex.trades[pairName] = trades
+	srv.ex.locker.Unlock()
+}
+
+// SetMarketTradesOpen set the response of camp.APIMarketTradesOpen for
+// pair.
+func (srv *Server) SetMarketTradesOpen(pairName string, open *camp.TradesOpen) {
+	srv.ex.locker.Lock()
+	srv.ex.tradesOpen[pairName] = open
+	srv.ex.locker.Unlock()
+}
+
+// SetUser set the response of camp.APIUserInfo.
+// The user is also used as the owner of order.
+// If user is nil, it will reset to the default user.
+func (srv *Server) SetUser(user *camp.User) {
+	if user == nil {
+		user = newUser()
+	}
+	srv.ex.locker.Lock()
+	srv.ex.user = user
+	srv.ex.locker.Unlock()
+}
+
+// SetUserTransactions set the deposit and withdraw history, the response
+// of camp.APIUserTransactions.
+// The successful withdraw request is appended into it.
+// If trans is nil, it will reset to empty history.
+func (srv *Server) SetUserTransactions(trans *camp.AssetTransactions) {
+	if trans == nil {
+		trans = newAssetTransactions()
+	}
+	if trans.Deposit == nil {
+		trans.Deposit = make(map[string][]camp.DepositItem)
+	}
+	if trans.Withdraw == nil {
+		trans.Withdraw = make(map[string][]camp.WithdrawItem)
+	}
+	srv.ex.locker.Lock()
+	srv.ex.trans = trans
+	srv.ex.locker.Unlock()
+}
+
+// ServeHTTP handle the HTTP request to REST API and the WebSocket
+// handshake on camp.WSPublic and camp.WSPrivate.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, httpreq *http.Request) {
+	switch httpreq.URL.Path {
+	case camp.WSPublic, camp.WSPrivate:
+		srv.serveWebSocket(w, httpreq)
+		return
+	}
+
+	body, err := io.ReadAll(httpreq.Body)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+
+	req := &Request{
+		Header: httpreq.Header,
+		Params: httpreq.URL.Query(),
+		Method: httpreq.Method,
+		Path:   httpreq.URL.Path,
+		Body:   body,
+	}
+
+	contentType := httpreq.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			writeResponse(w, nil, liberrors.InvalidInput("body"))
+			return
+		}
+		for k, v := range form {
+			req.Params[k] = append(req.Params[k], v...)
+		}
+	}
+
+	srv.record(req)
+
+	if isPrivate(req.Path) {
+		// The client sign the query parameters on GET and DELETE,
+		// and the request body on POST.
+		payload := httpreq.URL.RawQuery
+		if req.Method == http.MethodPost {
+			payload = string(body)
+		}
+		err = srv.verify(req.Header, payload)
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+	}
+
+	data, err := srv.call(req)
+	writeResponse(w, data, err)
+}
+
+// call the scripted handler of request, if its exist, or the default
+// handler.
+func (srv *Server) call(req *Request) (data interface{}, err error) {
+	handler := srv.hook(req)
+	if handler != nil {
+		return handler(req)
+	}
+
+	srv.ex.locker.Lock()
+	defer srv.ex.locker.Unlock()
+
+	data, err = srv.ex.call(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode the data while holding the lock, since its may refer to
+	// the exchange state.
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(raw), nil
+}
+
+// hook return the scripted handler for request, or nil if the request
+// should be handled by default handler.
+func (srv *Server) hook(req *Request) (handler HandlerFunc) {
+	key := routeKey(req.Method, req.Path)
+
+	srv.locker.Lock()
+	defer srv.locker.Unlock()
+
+	queue := srv.hooksOnce[key]
+	if len(queue) > 0 {
+		srv.hooksOnce[key] = queue[1:]
+		return queue[0]
+	}
+	return srv.hooks[key]
+}
+
+// record the request, so its can be inspected later using Requests.
+func (srv *Server) record(req *Request) {
+	srv.locker.Lock()
+	srv.requests = append(srv.requests, req)
+	srv.locker.Unlock()
+}
+
+// verify the Key and Sign in header with the signed payload.
+func (srv *Server) verify(header http.Header, payload string) error {
+	var (
+		key  = header.Get(camp.HeaderNameKey)
+		sign = header.Get(camp.HeaderNameSign)
+	)
+	if key != srv.Token || len(sign) == 0 {
+		return ErrUnauthorized
+	}
+	expSign := camp.Sign(payload, srv.Secret)
+	if !hmac.Equal([]byte(sign), []byte(expSign)) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// isPrivate return true if the path require authentication.
+func isPrivate(path string) bool {
+	return strings.HasPrefix(path, "/v2/user/") ||
+		strings.HasPrefix(path, "/v2/trade/")
+}
+
+// newResponse create the response envelope from data or error.
+func newResponse(data interface{}, err error) (res *camp.Response) {
+	res = &camp.Response{}
+	if err != nil {
+		errE := toErrorE(err)
+		res.Code = errE.Code
+		res.Message = errE.Message
+		res.Name = errE.Name
+		return res
+	}
+	res.Code = http.StatusOK
+	res.Data = data
+	return res
+}
+
+// toErrorE convert the err into *liberrors.E.
+// If the err is not *liberrors.E, it will return ErrInternal.
+func toErrorE(err error) *liberrors.E {
+	var errE *liberrors.E
+	if errors.As(err, &errE) && errE.Code != 0 {
+		return errE
+	}
+	return ErrInternal
+}
+
+// httpStatus return the code as HTTP status code.
+// The code outside the valid HTTP status code, 100 to 999, is only set in
+// the response body, while the HTTP status is set to 400, since it is
+// always come from error.
+func httpStatus(code int) int {
+	if code < 100 || code > 999 {
+		return http.StatusBadRequest
+	}
+	return code
+}
+
+func writeResponse(w http.ResponseWriter, data interface{}, err error) {
+	res := newResponse(data, err)
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		res = newResponse(nil, err)
+		body, _ = json.Marshal(res)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(res.Code))
+	_, err = w.Write(body)
+	if err != nil {
+		log.Printf("camptest: %s", err)
+	}
+}
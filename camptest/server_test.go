@@ -0,0 +1,423 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+
+	"github.com/campinvestment/camp-go"
+)
+
+func newTestClient(t *testing.T, env *camp.Environment) *camp.Client {
+	cl, err := camp.NewClient(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cl
+}
+
+// assertErrorE assert that the err is *liberrors.E with the same code and
+// name as exp.
+func assertErrorE(t *testing.T, desc string, exp *liberrors.E, err error) {
+	var errE *liberrors.E
+	if !errors.As(err, &errE) {
+		t.Fatalf("%s: want %v, got %v", desc, exp, err)
+	}
+	test.Assert(t, desc+": code", exp.Code, errE.Code)
+	test.Assert(t, desc+": name", exp.Name, errE.Name)
+}
+
+func TestServer_market(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	srv.SetMarketDepths(&camp.MarketDepths{
+		Pair: camp.PairBitcoinTether,
+		Asks: []*camp.Depth{{
+			Price:     big.NewRat(101),
+			TotalCoin: big.NewRat(1),
+		}},
+	})
+	srv.SetMarketTrades(camp.PairBitcoinTether, &camp.MarketTrades{
+		Asks: []camp.Trade{{ID: 1}, {ID: 2}, {ID: 3}},
+	})
+
+	cl := newTestClient(t, srv.Env())
+
+	depths, err := cl.MarketDepths(camp.PairBitcoinTether)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "MarketDepths", 1, len(depths.Asks))
+	test.Assert(t, "MarketDepths price", "101", depths.Asks[0].Price.String())
+
+	trades, err := cl.MarketTrades(camp.PairBitcoinTether, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "MarketTrades", []camp.Trade{{ID: 2}}, trades.Asks)
+
+	infos, err := cl.MarketInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "MarketInfo", 2, len(infos))
+}
+
+func TestServer_authentication(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	user, err := cl.UserInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserInfo ID", int64(1), user.ID)
+
+	env := srv.Env()
+	env.Secret = "invalid"
+	cl = newTestClient(t, env)
+
+	_, err = cl.UserInfo()
+	assertErrorE(t, "UserInfo", ErrUnauthorized, err)
+}
+
+func TestServer_trade(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	tres, err := cl.TradeBid(&camp.TradeRequest{
+		Pair:   camp.PairBitcoinTether,
+		Price:  big.NewRat(100),
+		Amount: big.NewRat(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "TradeBid type", camp.TradeTypeBid, tres.Order.Type)
+	test.Assert(t, "TradeBid base", "200", tres.Order.BaseAmount.String())
+
+	open, err := cl.UserOrdersOpen(camp.PairBitcoinTether)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserOrdersOpen", 1,
+		len(open[camp.PairBitcoinTether].Bids))
+
+	_, err = cl.TradeCancelAsk(camp.PairBitcoinTether, tres.Order.ID)
+	assertErrorE(t, "TradeCancelAsk", ErrOrderNotFound, err)
+
+	cres, err := cl.TradeCancelBid(camp.PairBitcoinTether, tres.Order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "TradeCancelBid status", camp.TradeStatusCancelled,
+		cres.Order.Status)
+
+	_, err = cl.TradeAsk(&camp.TradeRequest{
+		Pair:   "unknown_pair",
+		Price:  big.NewRat(100),
+		Amount: big.NewRat(2),
+	})
+	assertErrorE(t, "TradeAsk", camp.ErrInvalidPair, err)
+}
+
+func TestServer_tradeBulk(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	var (
+		cl     = newTestClient(t, srv.Env())
+		openID = srv.AddOrder(camp.Trade{
+			Pair: camp.PairBitcoinTether,
+			Type: camp.TradeTypeAsk,
+		})
+		closeID = srv.AddOrder(camp.Trade{
+			Pair:   camp.PairBitcoinTether,
+			Status: camp.TradeStatusFilled,
+		})
+		tbReq = &camp.TradeBulk{
+			Pair: camp.PairBitcoinTether,
+			Orders: []*camp.BulkOrderItem{{
+				TradeRequest: camp.TradeRequest{
+					Type:   camp.TradeTypeAsk,
+					Price:  big.NewRat(100),
+					Amount: big.NewRat(1),
+				},
+			}, {
+				TradeRequest: camp.TradeRequest{
+					Type:   camp.TradeTypeBid,
+					Price:  big.NewRat(100),
+					Amount: big.NewRat(0),
+				},
+			}},
+			Cancel: []*camp.BulkOrderItem{{
+				ID: openID,
+			}, {
+				ID: closeID,
+			}},
+		}
+	)
+
+	res, err := camp.ExecuteTradeBulk(cl, tbReq, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "OrdersSuccess", 1, res.OrdersSuccess)
+	test.Assert(t, "OrdersFailed", 1, res.OrdersFailed)
+	test.Assert(t, "Orders[1].Err", camp.ErrInvalidAmount.Name,
+		res.Orders[1].Err.Name)
+	test.Assert(t, "CancelSuccess", 1, res.CancelSuccess)
+	test.Assert(t, "CancelFailed", 1, res.CancelFailed)
+	test.Assert(t, "Cancel[1].Err", ErrOrderNotFound.Name,
+		res.Cancel[1].Err.Name)
+	test.Assert(t, "len(Orders)", 3, len(srv.Orders()))
+}
+
+func TestServer_userWithdraw(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	withdraw, err := cl.UserWithdraw(&camp.WithdrawRequest{
+		Amount:    big.NewRat(1),
+		RequestID: "req-1",
+		Asset:     camp.AssetNameBitcoin,
+		Address:   "address-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserWithdraw status", "pending", withdraw.Status)
+
+	trans, err := cl.UserTransactions(camp.AssetNameBitcoin, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserTransactions", 1,
+		len(trans.Withdraw[camp.AssetNameBitcoin]))
+}
+
+func TestServer_HandleOnce(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	errMaintenance := &liberrors.E{
+		Code:    http.StatusServiceUnavailable,
+		Message: "maintenance",
+		Name:    "ERR_MAINTENANCE",
+	}
+	srv.HandleOnce(http.MethodGet, camp.APIUserInfo,
+		func(req *Request) (interface{}, error) {
+			return nil, errMaintenance
+		})
+
+	cl := newTestClient(t, srv.Env())
+
+	_, err := cl.UserInfo()
+	assertErrorE(t, "UserInfo", errMaintenance, err)
+
+	// The next request use the default handler.
+	_, err = cl.UserInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := srv.Requests()
+	test.Assert(t, "len(Requests)", 2, len(reqs))
+	test.Assert(t, "Requests[0].Path", camp.APIUserInfo, reqs[0].Path)
+}
+
+func TestServer_SetUser(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	cases := []struct {
+		user  *camp.User
+		desc  string
+		expID int64
+	}{{
+		desc:  "custom user",
+		user:  &camp.User{ID: 7},
+		expID: 7,
+	}, {
+		desc:  "nil reset to default user",
+		expID: 1,
+	}}
+
+	for _, c := range cases {
+		srv.SetUser(c.user)
+
+		user, err := cl.UserInfo()
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+		test.Assert(t, c.desc+": UserInfo ID", c.expID, user.ID)
+
+		tres, err := cl.TradeBid(&camp.TradeRequest{
+			Pair:   camp.PairBitcoinTether,
+			Price:  big.NewRat(100),
+			Amount: big.NewRat(1),
+		})
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+		_, err = cl.TradeCancelBid(camp.PairBitcoinTether, tres.Order.ID)
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+	}
+}
+
+func TestServer_SetUserTransactions(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	srv.SetUserTransactions(&camp.AssetTransactions{
+		Withdraw: map[string][]camp.WithdrawItem{
+			camp.AssetNameBitcoin: {{Status: "success"}},
+		},
+	})
+
+	trans, err := cl.UserTransactions(camp.AssetNameBitcoin, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserTransactions", 1,
+		len(trans.Withdraw[camp.AssetNameBitcoin]))
+
+	srv.SetUserTransactions(nil)
+
+	trans, err = cl.UserTransactions(camp.AssetNameBitcoin, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserTransactions after reset", 0,
+		len(trans.Withdraw[camp.AssetNameBitcoin]))
+
+	_, err = cl.UserWithdraw(&camp.WithdrawRequest{
+		Amount:    big.NewRat(1),
+		RequestID: "req-1",
+		Asset:     camp.AssetNameBitcoin,
+		Address:   "address-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_errorCode(t *testing.T) {
+	type testCase struct {
+		desc      string
+		code      int
+		expStatus int
+	}
+
+	cases := []testCase{{
+		desc:      "code below HTTP status",
+		code:      1,
+		expStatus: http.StatusBadRequest,
+	}, {
+		desc:      "code above HTTP status",
+		code:      1001,
+		expStatus: http.StatusBadRequest,
+	}, {
+		desc:      "HTTP status",
+		code:      http.StatusServiceUnavailable,
+		expStatus: http.StatusServiceUnavailable,
+	}}
+
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	for _, c := range cases {
+		srv.HandleOnce(http.MethodGet, camp.APIMarketPrices,
+			func(req *Request) (interface{}, error) {
+				return nil, &liberrors.E{
+					Code: c.code,
+					Name: "ERR_CODE",
+				}
+			})
+
+		var (
+			w       = httptest.NewRecorder()
+			httpreq = httptest.NewRequest(http.MethodGet,
+				camp.APIMarketPrices, nil)
+			res = &camp.Response{}
+		)
+
+		srv.ServeHTTP(w, httpreq)
+
+		err := json.Unmarshal(w.Body.Bytes(), res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Assert(t, c.desc+": status", c.expStatus, w.Code)
+		test.Assert(t, c.desc+": code", c.code, res.Code)
+		test.Assert(t, c.desc+": name", "ERR_CODE", res.Name)
+	}
+}
+
+func TestServer_userTradesQuery(t *testing.T) {
+	type testCase struct {
+		desc     string
+		expQuery string
+		tp       camp.ListTradeParams
+	}
+
+	cases := []testCase{{
+		desc: "default sort and limit",
+		tp: camp.ListTradeParams{
+			Pair: camp.PairBitcoinTether,
+		},
+		expQuery: "limit=100&pair=btc_usdt&sort=desc",
+	}, {
+		desc: "limit greater than DefaultLimit",
+		tp: camp.ListTradeParams{
+			Pair:   camp.PairBitcoinTether,
+			Sort:   "ASC",
+			Limit:  1000,
+			Offset: 10,
+		},
+		expQuery: "limit=100&offset=10&pair=btc_usdt&sort=asc",
+	}}
+
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	cl := newTestClient(t, srv.Env())
+
+	for _, c := range cases {
+		_, err := cl.UserTrades(c.tp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reqs := srv.Requests()
+		params := reqs[len(reqs)-1].Params
+
+		// The timestamp changes on each request.
+		params.Del(camp.ParamNameTimestamp)
+
+		test.Assert(t, c.desc, c.expQuery, params.Encode())
+	}
+}
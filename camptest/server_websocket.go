@@ -0,0 +1,495 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+	"github.com/shuLhan/share/lib/websocket"
+
+	"github.com/campinvestment/camp-go"
+)
+
+// wsAcceptGUID define the GUID that concatenated with the client key to
+// create the Sec-WebSocket-Accept, as defined in RFC 6455 section 1.3.
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsVersion define the only version of WebSocket protocol supported by
+// server.
+// The server does not negotiate any extension on handshake, so the RSV
+// bits in frame from client must be zero.
+const wsVersion = "13"
+
+// DropWebSocket close all WebSocket connections on endpoint, camp.WSPublic
+// or camp.WSPrivate, without sending the close frame, to simulate the
+// connection lost.
+// If endpoint is empty, it will close all WebSocket connections.
+// It will return the number of connections that has been closed.
+func (srv *Server) DropWebSocket(endpoint string) (n int) {
+	for _, wsc := range srv.wsClients(endpoint) {
+		wsc.close()
+		n++
+	}
+	return n
+}
+
+// PushDepths broadcast the market depths to public clients that subscribe
+// to topic "depths" on its pair.
+// It will return the number of clients that receive the message.
+func (srv *Server) PushDepths(depths *camp.MarketDepths) int {
+	return srv.broadcast(camp.WSPublic, camp.APIMarketDepths, depths,
+		func(subs *camp.PublicSubscription) bool {
+			return hasPair(subs.Depths, depths.Pair)
+		})
+}
+
+// PushSummaries broadcast the market summaries to public clients that
+// subscribe to topic "summaries".
+// It will return the number of clients that receive the message.
+func (srv *Server) PushSummaries(summaries *camp.MarketSummaries) int {
+	return srv.broadcast(camp.WSPublic, camp.APIMarketSummaries, summaries,
+		func(subs *camp.PublicSubscription) bool {
+			return subs.Summaries
+		})
+}
+
+// PushTicker broadcast the ticker to public clients that subscribe to
+// topic "ticker" on its pair.
+// It will return the number of clients that receive the message.
+func (srv *Server) PushTicker(tick *camp.MarketTicker) int {
+	return srv.broadcast(camp.WSPublic, camp.APIMarketTicker, tick,
+		func(subs *camp.PublicSubscription) bool {
+			return hasPair(subs.Ticker, tick.PairName)
+		})
+}
+
+// PushTrade broadcast the trade to public clients that subscribe to topic
+// "trades" on its pair.
+// It will return the number of clients that receive the message.
+func (srv *Server) PushTrade(trade *camp.Trade) int {
+	return srv.broadcast(camp.WSPublic, camp.APIMarketTrades, trade,
+		func(subs *camp.PublicSubscription) bool {
+			return hasPair(subs.Trades, trade.Pair)
+		})
+}
+
+// PushOrderClosed broadcast the closed or cancelled user order to private
+// clients.
+// It will return the number of clients that receive the message.
+func (srv *Server) PushOrderClosed(trade *camp.Trade) int {
+	return srv.broadcast(camp.WSPrivate, camp.APIUserOrdersClosed, trade, nil)
+}
+
+// PushOrderTaken broadcast the partially filled user order to private
+// clients.
+// It will return the number of clients that receive the message.
+func (srv *Server) PushOrderTaken(trade *camp.Trade) int {
+	return srv.broadcast(camp.WSPrivate, camp.WSMessageUserOrdersTaken,
+		trade, nil)
+}
+
+// SetWebSocketDelay set the delay before responding each WebSocket
+// request, to simulate the slow server.
+// The broadcast messages are not delayed.
+func (srv *Server) SetWebSocketDelay(delay time.Duration) {
+	srv.locker.Lock()
+	srv.wsDelay = delay
+	srv.locker.Unlock()
+}
+
+// WebSocketClients return the number of active WebSocket connections on
+// endpoint, camp.WSPublic or camp.WSPrivate.
+// If endpoint is empty, it will return the number of all connections.
+func (srv *Server) WebSocketClients(endpoint string) int {
+	return len(srv.wsClients(endpoint))
+}
+
+// broadcast the v as message to all connections on endpoint that match
+// with their subscription.
+// If match is nil, the message is send to all connections on endpoint.
+func (srv *Server) broadcast(
+	endpoint, message string, v interface{},
+	match func(subs *camp.PublicSubscription) bool,
+) (n int) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("camptest: broadcast %s: %s", message, err)
+		return 0
+	}
+
+	packet, err := websocket.NewBroadcast(message,
+		base64.StdEncoding.EncodeToString(body))
+	if err != nil {
+		log.Printf("camptest: broadcast %s: %s", message, err)
+		return 0
+	}
+
+	for _, wsc := range srv.wsClients(endpoint) {
+		if match != nil {
+			srv.locker.Lock()
+			ok := match(wsc.subs)
+			srv.locker.Unlock()
+			if !ok {
+				continue
+			}
+		}
+		err = wsc.write(packet)
+		if err != nil {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// handleWSRequest handle the text payload from client as
+// websocket.Request and write the websocket.Response back to client.
+func (srv *Server) handleWSRequest(wsc *wsConn, payload []byte) {
+	wsreq := &websocket.Request{}
+
+	err := json.Unmarshal(payload, wsreq)
+	if err != nil {
+		log.Printf("camptest: %s: %q: %s", wsc.endpoint, payload, err)
+		return
+	}
+
+	srv.locker.Lock()
+	delay := srv.wsDelay
+	srv.locker.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	data, err := srv.callWS(wsc, wsreq)
+
+	wsres := &websocket.Response{
+		ID: wsreq.ID,
+	}
+	if err != nil {
+		errE := toErrorE(err)
+		wsres.Code = int32(errE.Code)
+		wsres.Message = errE.Message
+	} else {
+		wsres.Code = http.StatusOK
+
+		body, err := json.Marshal(data)
+		if err != nil {
+			errE := toErrorE(err)
+			wsres.Code = int32(errE.Code)
+			wsres.Message = errE.Message
+		} else {
+			wsres.Body = base64.StdEncoding.EncodeToString(body)
+		}
+	}
+
+	packet, err := json.Marshal(wsres)
+	if err != nil {
+		log.Printf("camptest: %s: %s", wsc.endpoint, err)
+		return
+	}
+
+	err = wsc.write(websocket.NewFrameText(false, packet))
+	if err != nil {
+		log.Printf("camptest: %s: %s", wsc.endpoint, err)
+	}
+}
+
+// callWS convert the WebSocket request into Request and call its handler.
+// The subscription request on camp.WSPublic is handled by the connection
+// itself.
+func (srv *Server) callWS(wsc *wsConn, wsreq *websocket.Request) (
+	data interface{}, err error,
+) {
+	body, err := base64.StdEncoding.DecodeString(wsreq.Body)
+	if err != nil {
+		return nil, liberrors.InvalidInput("body")
+	}
+
+	req := &Request{
+		Header: http.Header{},
+		Params: url.Values{},
+		Method: wsreq.Method,
+		Path:   wsreq.Target,
+		Body:   body,
+	}
+
+	// The TradeBulk send the raw JSON body, not the WebSocketParams.
+	if req.Path != camp.APITradeBulk && len(body) > 0 {
+		wsparams := &camp.WebSocketParams{}
+		err = wsparams.Unpack(body)
+		if err != nil {
+			return nil, ErrInvalidJSON
+		}
+		req.Params = wsParamsValues(wsparams)
+	}
+
+	srv.record(req)
+
+	if isPrivate(req.Path) != (wsc.endpoint == camp.WSPrivate) {
+		return nil, ErrNotFound
+	}
+
+	if req.Path == camp.WSPublicSubscription {
+		handler := srv.hook(req)
+		if handler != nil {
+			return handler(req)
+		}
+		return srv.subscription(wsc, req)
+	}
+
+	return srv.call(req)
+}
+
+// serveWebSocket upgrade the HTTP connection into WebSocket and serve the
+// requests from client until the connection closed.
+// On camp.WSPrivate, the client credential is verified before upgrading the
+// connection.
+func (srv *Server) serveWebSocket(w http.ResponseWriter, httpreq *http.Request) {
+	if httpreq.URL.Path == camp.WSPrivate {
+		err := srv.verify(httpreq.Header, httpreq.URL.RawQuery)
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+	}
+
+	key := httpreq.Header.Get("Sec-WebSocket-Key")
+	if len(key) == 0 ||
+		!strings.EqualFold(httpreq.Header.Get("Upgrade"), "websocket") {
+		writeResponse(w, nil, liberrors.InvalidInput("Upgrade"))
+		return
+	}
+	if httpreq.Header.Get("Sec-WebSocket-Version") != wsVersion {
+		writeResponse(w, nil,
+			liberrors.InvalidInput("Sec-WebSocket-Version"))
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeResponse(w, nil, ErrInternal)
+		return
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("camptest: %s: %s", httpreq.URL.Path, err)
+		return
+	}
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " +
+		base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+
+	_, err = conn.Write([]byte(handshake))
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	wsc := newWSConn(conn, rw.Reader, httpreq.URL.Path)
+
+	srv.locker.Lock()
+	srv.wsConns[wsc] = struct{}{}
+	srv.locker.Unlock()
+
+	defer func() {
+		srv.locker.Lock()
+		delete(srv.wsConns, wsc)
+		srv.locker.Unlock()
+		wsc.close()
+	}()
+
+	for {
+		opcode, payload, err := wsc.readFrame()
+		if err != nil {
+			if errors.Is(err, errFrameProtocol) {
+				_ = wsc.write(websocket.NewFrameClose(false,
+					websocket.StatusBadRequest, nil))
+			}
+			return
+		}
+		switch opcode {
+		case websocket.OpcodeText:
+			go srv.handleWSRequest(wsc, payload)
+		case websocket.OpcodePing:
+			_ = wsc.write(websocket.NewFramePong(false, payload))
+		case websocket.OpcodeClose:
+			_ = wsc.write(websocket.NewFrameClose(false,
+				websocket.StatusNormal, nil))
+			return
+		}
+	}
+}
+
+// subscription handle the request to camp.WSPublicSubscription.
+// The GET return the current subscription, the POST add the pairs and
+// topics in request, and the DELETE remove them.
+func (srv *Server) subscription(wsc *wsConn, req *Request) (
+	data interface{}, err error,
+) {
+	var subs camp.PublicSubscription
+
+	if len(req.Body) > 0 {
+		wsparams := &camp.WebSocketParams{}
+		err = wsparams.Unpack(req.Body)
+		if err != nil {
+			return nil, ErrInvalidJSON
+		}
+		subs = wsparams.PublicSubscription
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		srv.ex.locker.Lock()
+		for _, pairs := range [][]string{subs.Depths, subs.Ticker, subs.Trades} {
+			for _, pair := range pairs {
+				err = srv.ex.checkPair(pair)
+				if err != nil {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		srv.ex.locker.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	case http.MethodDelete:
+	default:
+		return nil, ErrNotFound
+	}
+
+	srv.locker.Lock()
+	defer srv.locker.Unlock()
+
+	cur := wsc.subs
+	switch req.Method {
+	case http.MethodPost:
+		cur.Depths = addPairs(cur.Depths, subs.Depths)
+		cur.Ticker = addPairs(cur.Ticker, subs.Ticker)
+		cur.Trades = addPairs(cur.Trades, subs.Trades)
+		cur.Summaries = cur.Summaries || subs.Summaries
+	case http.MethodDelete:
+		cur.Depths = removePairs(cur.Depths, subs.Depths)
+		cur.Ticker = removePairs(cur.Ticker, subs.Ticker)
+		cur.Trades = removePairs(cur.Trades, subs.Trades)
+		cur.Summaries = cur.Summaries && !subs.Summaries
+	}
+
+	return &camp.PublicSubscription{
+		Depths:    append([]string{}, cur.Depths...),
+		Ticker:    append([]string{}, cur.Ticker...),
+		Trades:    append([]string{}, cur.Trades...),
+		Summaries: cur.Summaries,
+	}, nil
+}
+
+// wsClients return the active WebSocket connections on endpoint.
+// If endpoint is empty, it will return all connections.
+func (srv *Server) wsClients(endpoint string) (list []*wsConn) {
+	srv.locker.Lock()
+	for wsc := range srv.wsConns {
+		if len(endpoint) == 0 || wsc.endpoint == endpoint {
+			list = append(list, wsc)
+		}
+	}
+	srv.locker.Unlock()
+	return list
+}
+
+// addPairs return the union of pairs and added, in order.
+func addPairs(pairs, added []string) []string {
+	for _, pair := range added {
+		if !hasPair(pairs, pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// hasPair return true if the pairName is in pairs.
+func hasPair(pairs []string, pairName string) bool {
+	for _, pair := range pairs {
+		if pair == pairName {
+			return true
+		}
+	}
+	return false
+}
+
+// removePairs return the pairs without the removed one.
+func removePairs(pairs, removed []string) (out []string) {
+	for _, pair := range pairs {
+		if !hasPair(removed, pair) {
+			out = append(out, pair)
+		}
+	}
+	return out
+}
+
+// wsParamsValues convert the WebSocketParams into query parameters, the
+// same parameters that send by REST client.
+func wsParamsValues(wsparams *camp.WebSocketParams) (params url.Values) {
+	params = url.Values{}
+
+	setString := func(key, value string) {
+		if len(value) > 0 {
+			params.Set(key, value)
+		}
+	}
+	setInt := func(key string, value int64) {
+		if value != 0 {
+			params.Set(key, strconv.FormatInt(value, 10))
+		}
+	}
+
+	treq := &wsparams.TradeRequest
+	setString(camp.ParamNamePair, treq.Pair)
+	setString(camp.ParamNameTradeMethod, treq.Method)
+	if treq.Amount != nil {
+		params.Set(camp.ParamNameAmount, treq.Amount.String())
+	}
+	if treq.Price != nil {
+		params.Set(camp.ParamNamePrice, treq.Price.String())
+	}
+	if treq.IsPostOnly {
+		params.Set(camp.ParamNamePostOnly, "true")
+	}
+
+	setString(camp.ParamNameAddress, wsparams.Address)
+	setString(camp.ParamNameAddressType, wsparams.AddressType)
+	setString(camp.ParamNameAsset, wsparams.Asset)
+	setString(camp.ParamNameMemo, wsparams.Memo)
+	setString(camp.ParamNameNetwork, wsparams.Network)
+	setString(camp.ParamNameRequestID, wsparams.RequestID)
+	setString(camp.ParamNameSort, wsparams.IDSortBy)
+
+	setInt(camp.ParamNameIDAfter, wsparams.IDAfter)
+	setInt(camp.ParamNameIDBefore, wsparams.IDBefore)
+	setInt(camp.ParamNameTimeAfter, wsparams.TimeAfter)
+	setInt(camp.ParamNameTimeBefore, wsparams.TimeBefore)
+	setInt(camp.ParamNameTradeID, wsparams.TradeID)
+	setInt(camp.ParamNameLimit, wsparams.Limit)
+	setInt(camp.ParamNameOffset, wsparams.Offset)
+
+	return params
+}
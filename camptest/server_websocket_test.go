@@ -0,0 +1,570 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+
+	"github.com/campinvestment/camp-go"
+)
+
+func newTestWSOptions() *camp.WebSocketOptions {
+	return &camp.WebSocketOptions{
+		Timeout: 5 * time.Second,
+		Reconnect: &camp.ReconnectPolicy{
+			InitialDelay: 10 * time.Millisecond,
+			MaxDelay:     50 * time.Millisecond,
+		},
+	}
+}
+
+func TestServer_webSocketPublic(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	ws, err := camp.NewWebSocketPublicWithOptions(srv.Env(),
+		newTestWSOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	infos, err := ws.MarketInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "MarketInfo", 2, len(infos))
+
+	subs, err := ws.SubscribeDepths([]string{camp.PairBitcoinTether})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "SubscribeDepths", []string{camp.PairBitcoinTether},
+		subs.Depths)
+
+	// The WebSocket client return the error from response message only.
+	_, err = ws.SubscribeTicker([]string{"unknown_pair"})
+	test.Assert(t, "SubscribeTicker", camp.ErrInvalidPair.Message,
+		err.Error())
+
+	depths := &camp.MarketDepths{
+		Pair: camp.PairBitcoinTether,
+		Bids: []*camp.Depth{{
+			Price:     big.NewRat(99),
+			TotalCoin: big.NewRat(1),
+		}},
+	}
+
+	test.Assert(t, "PushTicker", 0, srv.PushTicker(&camp.MarketTicker{
+		PairName: camp.PairBitcoinTether,
+	}))
+	test.Assert(t, "PushDepths", 1, srv.PushDepths(depths))
+
+	got := <-ws.NotifDepths
+	test.Assert(t, "NotifDepths", "99", got.Bids[0].Price.String())
+
+	test.Assert(t, "DropWebSocket", 1, srv.DropWebSocket(camp.WSPublic))
+
+	subs = <-ws.NotifReconnected
+	test.Assert(t, "NotifReconnected", []string{camp.PairBitcoinTether},
+		subs.Depths)
+
+	test.Assert(t, "PushDepths after reconnect", 1, srv.PushDepths(depths))
+
+	got = <-ws.NotifDepths
+	test.Assert(t, "NotifDepths after reconnect", camp.PairBitcoinTether,
+		got.Pair)
+}
+
+func TestServer_webSocketPrivate(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	ws, err := camp.NewWebSocketPrivateWithOptions(srv.Env(),
+		newTestWSOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	user, err := ws.UserInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UserInfo ID", int64(1), user.ID)
+
+	tres, err := ws.TradeBid(&camp.TradeRequest{
+		Pair:   camp.PairBitcoinTether,
+		Price:  big.NewRat(100),
+		Amount: big.NewRat(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "TradeBid base", "200", tres.Order.BaseAmount.String())
+
+	chev := make(chan *camp.PrivateEvent, 1)
+	remove := ws.Listen(func(ev *camp.PrivateEvent) {
+		chev <- ev
+	})
+	defer remove()
+
+	test.Assert(t, "PushOrderTaken", 1, srv.PushOrderTaken(tres.Order))
+
+	ev := <-chev
+	test.Assert(t, "PrivateEvent.Type", camp.PrivateEventOrderTaken, ev.Type)
+	test.Assert(t, "PrivateEvent.Trade.ID", tres.Order.ID, ev.Trade.ID)
+
+	env := srv.Env()
+	env.Secret = "invalid"
+
+	_, err = camp.NewWebSocketPrivateWithOptions(env, newTestWSOptions())
+	if err == nil {
+		t.Fatal("NewWebSocketPrivateWithOptions: want error, got nil")
+	}
+}
+
+func TestServer_SetWebSocketDelay(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	opts := newTestWSOptions()
+	opts.Timeout = 50 * time.Millisecond
+
+	ws, err := camp.NewWebSocketPrivateWithOptions(srv.Env(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	srv.SetWebSocketDelay(200 * time.Millisecond)
+
+	_, err = ws.UserInfo()
+	assertErrorE(t, "UserInfo", camp.ErrWebSocketTimeout, err)
+
+	srv.SetWebSocketDelay(0)
+
+	_, err = ws.UserInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_sessionRecorder(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	// The public and private clients share the same options and
+	// recorder.
+	var (
+		buf  = &bytes.Buffer{}
+		opts = newTestWSOptions()
+	)
+	opts.Recorder = camp.NewSessionRecorder(buf)
+
+	pub, err := camp.NewWebSocketPublicWithOptions(srv.Env(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := camp.NewWebSocketPrivateWithOptions(srv.Env(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pub.SubscribeDepths([]string{camp.PairBitcoinTether})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chev := make(chan *camp.PrivateEvent, 1)
+	remove := priv.Listen(func(ev *camp.PrivateEvent) {
+		chev <- ev
+	})
+
+	srv.PushDepths(&camp.MarketDepths{Pair: camp.PairBitcoinTether})
+	<-pub.NotifDepths
+
+	srv.PushOrderClosed(&camp.Trade{ID: 7, Status: camp.TradeStatusFilled})
+	<-chev
+
+	remove()
+	_ = pub.Close()
+	_ = priv.Close()
+
+	rp, err := camp.NewSessionReplayer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, frame := range rp.Frames {
+		key := frame.Target + frame.Message
+		got[key] = frame.Endpoint
+	}
+
+	exp := map[string]string{
+		camp.WSPublicSubscription: camp.WSPublic,
+		camp.APIMarketDepths:      camp.WSPublic,
+		camp.APIUserOrdersClosed:  camp.WSPrivate,
+	}
+	for key, endpoint := range exp {
+		test.Assert(t, key, endpoint, got[key])
+	}
+
+	replay := camp.NewWebSocketPrivateReplay(opts)
+	defer replay.Close()
+
+	chev = make(chan *camp.PrivateEvent, 2)
+	replay.Listen(func(ev *camp.PrivateEvent) {
+		chev <- ev
+	})
+
+	err = rp.Replay(replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev := <-chev
+	test.Assert(t, "replayed Message", camp.APIUserOrdersClosed, ev.Message)
+	select {
+	case ev = <-chev:
+		t.Fatalf("replayed public frame %s on private client", ev.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServer_webSocketClose(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	var (
+		chstate = make(chan camp.WebSocketState, 10)
+		opts    = newTestWSOptions()
+	)
+	opts.HandleEvent = func(ev *camp.WebSocketEvent) {
+		chstate <- ev.State
+	}
+
+	pub, err := camp.NewWebSocketPublicWithOptions(srv.Env(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := camp.NewWebSocketPrivateWithOptions(srv.Env(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Consume the states from connecting the public and private
+	// clients.
+	for x := 0; x < 4; x++ {
+		<-chstate
+	}
+
+	_ = pub.Close()
+	_ = priv.Close()
+
+	var got []camp.WebSocketState
+	timeout := time.After(200 * time.Millisecond)
+	for len(got) < 3 {
+		select {
+		case state := <-chstate:
+			got = append(got, state)
+			continue
+		case <-timeout:
+		}
+		break
+	}
+
+	exp := []camp.WebSocketState{
+		camp.WebSocketStateClosed,
+		camp.WebSocketStateClosed,
+	}
+	test.Assert(t, "states after Close", exp, got)
+}
+
+func TestServer_webSocketPublicPool(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	srv.SetMarketInfo([]camp.MarketInfo{
+		newMarketInfo("a", camp.AssetNameTether),
+		newMarketInfo("b", camp.AssetNameTether),
+		newMarketInfo("c", camp.AssetNameTether),
+		newMarketInfo("d", camp.AssetNameTether),
+	})
+
+	pool, err := camp.NewWebSocketPublicPool(srv.Env(), 2,
+		newTestWSOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	_, err = pool.SetTopic(camp.TopicDepths,
+		[]string{"a_usdt", "b_usdt", "c_usdt", "d_usdt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "Shard c_usdt", 0, pool.Shard("c_usdt"))
+
+	// Removing the pairs on the second connection move the "c_usdt"
+	// from the first connection.
+	nreq := len(srv.Requests())
+	_, err = pool.SetTopic(camp.TopicDepths, []string{"a_usdt", "c_usdt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "Shard c_usdt after rebalance", 1, pool.Shard("c_usdt"))
+
+	var gotSubs []string
+	for _, req := range srv.Requests()[nreq:] {
+		if req.Path != camp.WSPublicSubscription ||
+			req.Method == http.MethodGet {
+			continue
+		}
+		wsparams := &camp.WebSocketParams{}
+		err = wsparams.Unpack(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pair := range wsparams.Depths {
+			gotSubs = append(gotSubs, req.Method+" "+pair)
+		}
+	}
+	expSubs := []string{
+		"POST c_usdt",
+		"DELETE c_usdt",
+		"DELETE b_usdt",
+		"DELETE d_usdt",
+	}
+	test.Assert(t, "subscribe before unsubscribe", expSubs, gotSubs)
+
+	// The notifications from all connections are merged.
+	for _, pair := range []string{"a_usdt", "c_usdt"} {
+		test.Assert(t, "PushDepths "+pair, 1,
+			srv.PushDepths(&camp.MarketDepths{Pair: pair}))
+		got := <-pool.NotifDepths
+		test.Assert(t, "NotifDepths", pair, got.Pair)
+	}
+
+	// The failed subscription does not change the desired subscription
+	// and pair assignment.
+	_, err = pool.SetTopic(camp.TopicDepths,
+		[]string{"a_usdt", "b_usdt", "c_usdt", "unknown"})
+	if err == nil {
+		t.Fatal("SetTopic: want error on unknown pair, got nil")
+	}
+	test.Assert(t, "Desired after error", []string{"a_usdt", "c_usdt"},
+		pool.Desired().Depths)
+	test.Assert(t, "Shard b_usdt after error", -1, pool.Shard("b_usdt"))
+	test.Assert(t, "PushDepths b_usdt after error", 0,
+		srv.PushDepths(&camp.MarketDepths{Pair: "b_usdt"}))
+	test.Assert(t, "PushDepths c_usdt after error", 1,
+		srv.PushDepths(&camp.MarketDepths{Pair: "c_usdt"}))
+}
+
+// dialWebSocket open raw connection to server and do the WebSocket
+// handshake with version.
+func dialWebSocket(t *testing.T, srv *Server, version string) (
+	conn net.Conn, r *bufio.Reader, res *http.Response,
+) {
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handshake := "GET " + camp.WSPublic + " HTTP/1.1\r\n" +
+		"Host: " + srv.Listener.Addr().String() + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Version: " + version + "\r\n\r\n"
+
+	_, err = conn.Write([]byte(handshake))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = bufio.NewReader(conn)
+	res, err = http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, r, res
+}
+
+func TestServer_webSocketFrame(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	conn, _, res := dialWebSocket(t, srv, "8")
+	_ = conn.Close()
+	test.Assert(t, "handshake with version 8", http.StatusBadRequest,
+		res.StatusCode)
+
+	// The close frame with status 1002 (protocol error).
+	frameClose := []byte{0x88, 0x02, 0x03, 0xEA}
+
+	cases := []struct {
+		desc  string
+		frame []byte
+		exp   []byte
+	}{{
+		desc:  "ping",
+		frame: []byte{0x89, 0x00},
+		exp:   []byte{0x8A, 0x00},
+	}, {
+		desc:  "FIN bit unset",
+		frame: []byte{0x01, 0x00},
+		exp:   frameClose,
+	}, {
+		desc:  "RSV1 bit set",
+		frame: []byte{0xC1, 0x00},
+		exp:   frameClose,
+	}, {
+		desc:  "continuation frame",
+		frame: []byte{0x80, 0x00},
+		exp:   frameClose,
+	}}
+
+	for _, c := range cases {
+		conn, r, res := dialWebSocket(t, srv, "13")
+		test.Assert(t, c.desc+": handshake",
+			http.StatusSwitchingProtocols, res.StatusCode)
+
+		_, err := conn.Write(c.frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		got := make([]byte, len(c.exp))
+		_, err = io.ReadFull(r, got)
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+		test.Assert(t, c.desc, c.exp, got)
+
+		_ = conn.Close()
+	}
+}
+
+func TestServer_webSocketPublicTicker(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	ws, err := camp.NewWebSocketPublicWithOptions(srv.Env(),
+		newTestWSOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	subs, err := ws.SubscribeTicker([]string{
+		camp.PairBitcoinTether,
+		camp.PairEthereumTether,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "SubscribeTicker", []string{
+		camp.PairBitcoinTether,
+		camp.PairEthereumTether,
+	}, subs.Ticker)
+
+	tick := &camp.MarketTicker{
+		LowestAskPrice:  big.NewRat("101.5"),
+		HighestBidPrice: big.NewRat(99),
+		HighestPrice24H: big.NewRat(110),
+		LowestPrice24H:  big.NewRat(90),
+		LastPrice:       big.NewRat(100),
+		VolumeBase24H:   big.NewRat(2000),
+		VolumeCoin24H:   big.NewRat(20),
+		PairName:        camp.PairBitcoinTether,
+	}
+
+	test.Assert(t, "PushTicker", 1, srv.PushTicker(tick))
+	got := <-ws.NotifTicker
+	test.Assert(t, "NotifTicker", *tick, got)
+
+	subs, err = ws.UnsubscribeTicker([]string{camp.PairBitcoinTether})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UnsubscribeTicker", []string{camp.PairEthereumTether},
+		subs.Ticker)
+	test.Assert(t, "PushTicker after unsubscribe", 0, srv.PushTicker(tick))
+
+	// Empty pairs unsubscribe all pairs.
+	subs, err = ws.UnsubscribeTicker(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UnsubscribeTicker all", 0, len(subs.Ticker))
+	test.Assert(t, "PushTicker ETH after unsubscribe all", 0,
+		srv.PushTicker(&camp.MarketTicker{
+			PairName: camp.PairEthereumTether,
+		}))
+}
+
+func TestServer_webSocketPublicSummaries(t *testing.T) {
+	srv := NewServer("", "")
+	defer srv.Close()
+
+	ws, err := camp.NewWebSocketPublicWithOptions(srv.Env(),
+		newTestWSOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	subs, err := ws.SubscribeSummaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "SubscribeSummaries", true, subs.Summaries)
+
+	sums := &camp.MarketSummaries{
+		Prices: map[string]*big.Rat{
+			camp.PairBitcoinTether: big.NewRat(100),
+		},
+		Prices24h: map[string]*big.Rat{
+			camp.PairBitcoinTether: big.NewRat(95),
+		},
+		Prices7d: map[string]*big.Rat{
+			camp.PairBitcoinTether: big.NewRat(80),
+		},
+		PricesChanges: map[string]*big.Rat{
+			camp.PairBitcoinTether: big.NewRat("5.26"),
+		},
+		Tickers: map[string]camp.MarketTicker{
+			camp.PairBitcoinTether: {
+				LastPrice: big.NewRat(100),
+				PairName:  camp.PairBitcoinTether,
+			},
+		},
+	}
+
+	test.Assert(t, "PushSummaries", 1, srv.PushSummaries(sums))
+	got := <-ws.NotifSummaries
+	test.Assert(t, "NotifSummaries", *sums, got)
+
+	subs, err = ws.UnsubscribeSummaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "UnsubscribeSummaries", false, subs.Summaries)
+	test.Assert(t, "PushSummaries after unsubscribe", 0,
+		srv.PushSummaries(sums))
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camptest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/shuLhan/share/lib/websocket"
+
+	"github.com/campinvestment/camp-go"
+)
+
+// maxFramePayload define the maximum payload of frame received from
+// client.
+const maxFramePayload = 16 * 1024 * 1024
+
+// List of errors when reading frame from client.
+var (
+	// errFrameProtocol define an error when the client send the
+	// fragmented frame or the frame with RSV bits set, since the
+	// server does not support fragmentation and extensions.
+	errFrameProtocol = errors.New("fragmented frame or RSV bits set")
+
+	// errFrameTooLarge define an error when the client send frame
+	// larger than maxFramePayload.
+	errFrameTooLarge = errors.New("frame payload too large")
+)
+
+// wsConn is the WebSocket connection from client on endpoint camp.WSPublic
+// or camp.WSPrivate.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	// subs contains the topics subscribed by client on camp.WSPublic.
+	subs *camp.PublicSubscription
+
+	endpoint string
+
+	// wlock serialize the write to conn.
+	wlock sync.Mutex
+
+	isClosed atomic.Bool
+}
+
+func newWSConn(conn net.Conn, r *bufio.Reader, endpoint string) *wsConn {
+	return &wsConn{
+		conn:     conn,
+		r:        r,
+		subs:     &camp.PublicSubscription{},
+		endpoint: endpoint,
+	}
+}
+
+// close the underlying connection without sending the close frame, like
+// the connection lost.
+func (wsc *wsConn) close() {
+	if wsc.isClosed.Swap(true) {
+		return
+	}
+	_ = wsc.conn.Close()
+}
+
+// readFrame read the next frame from client.
+// The payload of masked frame is unmasked.
+// The fragmented frame is not supported, since the client does not send
+// it, and no extension is negotiated on handshake, so the frame with FIN
+// bit unset, RSV bits set, or continuation opcode return errFrameProtocol.
+func (wsc *wsConn) readFrame() (opcode websocket.Opcode, payload []byte, err error) {
+	var header [2]byte
+
+	_, err = io.ReadFull(wsc.r, header[:])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		isFin = header[0]&0x80 != 0
+		rsv   = header[0] & 0x70
+	)
+
+	opcode = websocket.Opcode(header[0] & 0x0F)
+	if !isFin || rsv != 0 || opcode == websocket.OpcodeCont {
+		return 0, nil, errFrameProtocol
+	}
+
+	var (
+		isMasked = header[1]&0x80 != 0
+		size     = uint64(header[1] & 0x7F)
+	)
+
+	switch size {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(wsc.r, ext[:])
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(wsc.r, ext[:])
+		if err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > maxFramePayload {
+		return 0, nil, errFrameTooLarge
+	}
+
+	var mask [4]byte
+	if isMasked {
+		_, err = io.ReadFull(wsc.r, mask[:])
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, size)
+	_, err = io.ReadFull(wsc.r, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	if isMasked {
+		for x := range payload {
+			payload[x] ^= mask[x%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// write the packet, the frame created by websocket.NewFrame*, to client.
+func (wsc *wsConn) write(packet []byte) (err error) {
+	wsc.wlock.Lock()
+	_, err = wsc.conn.Write(packet)
+	wsc.wlock.Unlock()
+	return err
+}
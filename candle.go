@@ -0,0 +1,143 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+)
+
+// List of common candle intervals.
+const (
+	CandleInterval1m = time.Minute
+	CandleInterval5m = 5 * time.Minute
+	CandleInterval1h = time.Hour
+	CandleInterval1d = 24 * time.Hour
+)
+
+// CandleHandler define the callback when the candle is closed.
+type CandleHandler func(candle *Candle)
+
+// Candle contains the OHLCV (open, high, low, close, and volume) of trades
+// on specific pair within an interval.
+type Candle struct {
+	Open  *big.Rat `json:"open"`
+	High  *big.Rat `json:"high"`
+	Low   *big.Rat `json:"low"`
+	Close *big.Rat `json:"close"`
+
+	// VolumeBase is the total base amount that has been traded.
+	VolumeBase *big.Rat `json:"volume_base"`
+
+	// VolumeCoin is the total coin amount that has been traded.
+	VolumeCoin *big.Rat `json:"volume_coin"`
+
+	// VWAP is the volume weighted average price, VolumeBase divided by
+	// VolumeCoin.
+	VWAP *big.Rat `json:"vwap"`
+
+	Pair string `json:"pair"`
+
+	// Interval of candle.
+	Interval time.Duration `json:"interval"`
+
+	// StartTime is the Unix time in seconds when the candle started,
+	// inclusive.
+	StartTime int64 `json:"start_time"`
+
+	// Count is the number of trades in the candle.
+	Count int64 `json:"count"`
+
+	// LastTradeID is the greatest trade ID in the candle.
+	LastTradeID int64 `json:"last_trade_id"`
+
+	// firstTime and firstID are the finish time and ID of the trade
+	// that set the Open.
+	firstTime int64
+	firstID   int64
+
+	// lastTime and lastID are the finish time and ID of the trade that
+	// set the Close.
+	lastTime int64
+	lastID   int64
+}
+
+// newCandle create new candle that started at start.
+func newCandle(pair string, interval time.Duration, start int64) *Candle {
+	return &Candle{
+		Pair:       pair,
+		Interval:   interval,
+		StartTime:  start,
+		VolumeBase: big.NewRat(0),
+		VolumeCoin: big.NewRat(0),
+	}
+}
+
+// EndTime return the Unix time in seconds when the candle end,
+// exclusive.
+func (candle *Candle) EndTime() int64 {
+	return candle.StartTime + int64(candle.Interval/time.Second)
+}
+
+// add the trade price and amounts into candle.
+// The Open and Close are the price of the earliest and the latest trade by
+// finish time, and then by ID if their finish time are equal, so the trade
+// can be added out of order.
+func (candle *Candle) add(price, base, coin *big.Rat, id, finish int64) {
+	if candle.Count == 0 {
+		candle.Open = big.NewRat(price)
+		candle.High = big.NewRat(price)
+		candle.Low = big.NewRat(price)
+		candle.Close = big.NewRat(price)
+		candle.firstTime, candle.firstID = finish, id
+		candle.lastTime, candle.lastID = finish, id
+	} else {
+		if price.IsGreater(candle.High) {
+			candle.High = big.NewRat(price)
+		}
+		if price.IsLess(candle.Low) {
+			candle.Low = big.NewRat(price)
+		}
+		if isTradeBefore(finish, id, candle.firstTime, candle.firstID) {
+			candle.Open = big.NewRat(price)
+			candle.firstTime, candle.firstID = finish, id
+		}
+		if isTradeBefore(candle.lastTime, candle.lastID, finish, id) {
+			candle.Close = big.NewRat(price)
+			candle.lastTime, candle.lastID = finish, id
+		}
+	}
+
+	candle.VolumeBase.Add(base)
+	candle.VolumeCoin.Add(coin)
+	if candle.VolumeCoin.IsGreaterThanZero() {
+		candle.VWAP = big.QuoRat(candle.VolumeBase, candle.VolumeCoin)
+	}
+
+	candle.Count++
+	if id > candle.LastTradeID {
+		candle.LastTradeID = id
+	}
+}
+
+// isTradeBefore return true if the trade with finish time ta and ID ida
+// happened before the trade with finish time tb and ID idb.
+func isTradeBefore(ta, ida, tb, idb int64) bool {
+	if ta != tb {
+		return ta < tb
+	}
+	return ida < idb
+}
+
+// candleStart return the start time of candle with interval that contains
+// the time t, in Unix seconds.
+func candleStart(t int64, interval time.Duration) int64 {
+	sec := int64(interval / time.Second)
+	if sec <= 0 {
+		return t
+	}
+	return t - t%sec
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+)
+
+// DefaultCandleDedupSize define the default number of the latest trade IDs
+// that are remembered to detect duplicate trades.
+const DefaultCandleDedupSize = 10000
+
+// CandleAggregator aggregate the trades into candles on each pair and
+// intervals.
+//
+// The trade is placed into candle by its FinishTime.
+// Only trade with non-zero FinishTime and CoinFilled are aggregated, so
+// the open orders from NotifTrades are ignored.
+// The trade with the same ID as one of the recent trades is ignored.
+//
+// The candle is closed, and passed to HandleClose, once the latest
+// FinishTime on the pair pass the candle end time plus Lateness.
+// The trade that belong to the candle that has been closed, on any of the
+// intervals, is not aggregated into all intervals and passed to
+// HandleLate, so the candles on different intervals always contain the
+// same trades.
+// Since the candle only closed by the next trade, call Flush periodically
+// to close the candles on pair with low activity.
+// The candle without any trades is not created.
+type CandleAggregator struct {
+	// HandleClose define the callback that will be called when the
+	// candle is closed.
+	HandleClose CandleHandler
+
+	// HandleLate define the callback that will be called when the
+	// trade is not aggregated because its candle on one of the
+	// intervals has been closed.
+	HandleLate func(trade *Trade)
+
+	// pairs contains the state of each pair.
+	pairs map[string]*candlePair
+
+	// seen and seenIDs contains the recent trade IDs, for
+	// de-duplication.
+	seen    map[int64]struct{}
+	seenIDs []int64
+
+	intervals []time.Duration
+
+	// Lateness define how long the candle is kept open after its end
+	// time to accept the late trades.
+	// Default to zero, which close the candle on the first trade after
+	// its end time.
+	Lateness time.Duration
+
+	// DedupSize define the number of the latest trade IDs that are
+	// remembered for de-duplication.
+	// Default to DefaultCandleDedupSize.
+	DedupSize int
+
+	locker sync.Mutex
+}
+
+// candlePair contains the open candles and the closed time for each
+// interval on pair.
+type candlePair struct {
+	// open candles indexed by interval and start time.
+	open map[time.Duration]map[int64]*Candle
+
+	// closedUntil contains the end time of the last closed candle
+	// on each interval.
+	closedUntil map[time.Duration]int64
+
+	// lastTime is the greatest FinishTime on the pair.
+	lastTime int64
+}
+
+// NewCandleAggregator create new aggregator for the list of intervals,
+// for example CandleInterval1m and CandleInterval1h.
+// The interval must be in multiple of seconds.
+func NewCandleAggregator(intervals ...time.Duration) (agg *CandleAggregator) {
+	agg = &CandleAggregator{
+		pairs: make(map[string]*candlePair),
+		seen:  make(map[int64]struct{}),
+	}
+	for _, interval := range intervals {
+		if interval < time.Second {
+			continue
+		}
+		agg.intervals = append(agg.intervals, interval)
+	}
+	return agg
+}
+
+// Add aggregate the trade into candles.
+// It return false if the trade is ignored, duplicate, or late on one of
+// the intervals.
+// The late trade is not aggregated into any interval.
+func (agg *CandleAggregator) Add(trade *Trade) bool {
+	if trade == nil || trade.FinishTime <= 0 ||
+		!trade.CoinFilled.IsGreaterThanZero() {
+		return false
+	}
+
+	// The trade without price and base filled can not be aggregated.
+	price, base := tradePriceBase(trade)
+	if !price.IsGreaterThanZero() || base == nil {
+		return false
+	}
+
+	var (
+		closed []*Candle
+		isLate bool
+	)
+
+	agg.locker.Lock()
+	if agg.isDuplicate(trade.ID) {
+		agg.locker.Unlock()
+		return false
+	}
+
+	cp := agg.pair(trade.Pair)
+	for _, interval := range agg.intervals {
+		start := candleStart(trade.FinishTime, interval)
+		if start < cp.closedUntil[interval] {
+			isLate = true
+			break
+		}
+	}
+	if isLate {
+		agg.locker.Unlock()
+		if agg.HandleLate != nil {
+			agg.HandleLate(trade)
+		}
+		return false
+	}
+
+	for _, interval := range agg.intervals {
+		start := candleStart(trade.FinishTime, interval)
+		candle := cp.open[interval][start]
+		if candle == nil {
+			candle = newCandle(trade.Pair, interval, start)
+			cp.open[interval][start] = candle
+		}
+		candle.add(price, base, trade.CoinFilled, trade.ID,
+			trade.FinishTime)
+	}
+	if trade.FinishTime > cp.lastTime {
+		cp.lastTime = trade.FinishTime
+	}
+	closed = agg.closeUntil(cp, cp.lastTime-int64(agg.Lateness/time.Second))
+	agg.locker.Unlock()
+
+	agg.emit(closed)
+
+	return true
+}
+
+// Flush close all candles that end before time now minus Lateness.
+func (agg *CandleAggregator) Flush(now time.Time) {
+	var (
+		until  = now.Unix() - int64(agg.Lateness/time.Second)
+		closed []*Candle
+	)
+
+	agg.locker.Lock()
+	for _, cp := range agg.pairs {
+		closed = append(closed, agg.closeUntil(cp, until)...)
+	}
+	agg.locker.Unlock()
+
+	agg.emit(closed)
+}
+
+// closeUntil close the open candles on pair that end before or at time
+// until, sorted by start time.
+// The caller must hold the lock.
+func (agg *CandleAggregator) closeUntil(cp *candlePair, until int64) (
+	closed []*Candle,
+) {
+	for _, interval := range agg.intervals {
+		var list []*Candle
+		for start, candle := range cp.open[interval] {
+			if candle.EndTime() > until {
+				continue
+			}
+			list = append(list, candle)
+			delete(cp.open[interval], start)
+		}
+		sort.Slice(list, func(x, y int) bool {
+			return list[x].StartTime < list[y].StartTime
+		})
+		for _, candle := range list {
+			if candle.EndTime() > cp.closedUntil[interval] {
+				cp.closedUntil[interval] = candle.EndTime()
+			}
+		}
+		closed = append(closed, list...)
+	}
+	return closed
+}
+
+func (agg *CandleAggregator) emit(closed []*Candle) {
+	if agg.HandleClose == nil {
+		return
+	}
+	for _, candle := range closed {
+		agg.HandleClose(candle)
+	}
+}
+
+// isDuplicate return true if the trade ID has been seen before, otherwise
+// remember it.
+// The caller must hold the lock.
+func (agg *CandleAggregator) isDuplicate(id int64) bool {
+	if id == 0 {
+		return false
+	}
+	_, ok := agg.seen[id]
+	if ok {
+		return true
+	}
+
+	size := agg.DedupSize
+	if size <= 0 {
+		size = DefaultCandleDedupSize
+	}
+	if len(agg.seenIDs) >= size {
+		delete(agg.seen, agg.seenIDs[0])
+		agg.seenIDs = agg.seenIDs[1:]
+	}
+	agg.seen[id] = struct{}{}
+	agg.seenIDs = append(agg.seenIDs, id)
+	return false
+}
+
+// pair return the state of pair, create new one if its not exist.
+// The caller must hold the lock.
+func (agg *CandleAggregator) pair(name string) (cp *candlePair) {
+	cp = agg.pairs[name]
+	if cp != nil {
+		return cp
+	}
+	cp = &candlePair{
+		open:        make(map[time.Duration]map[int64]*Candle),
+		closedUntil: make(map[time.Duration]int64),
+	}
+	for _, interval := range agg.intervals {
+		cp.open[interval] = make(map[int64]*Candle)
+	}
+	agg.pairs[name] = cp
+	return cp
+}
+
+// tradePriceBase return the price and base amount of filled trade.
+// If the trade does not have price, for example market order, the price
+// is the base filled divided by coin filled.
+// If the trade does not have base filled, it is the price multiplied by
+// coin filled.
+func tradePriceBase(trade *Trade) (price, base *big.Rat) {
+	price = trade.Price
+	if !price.IsGreaterThanZero() {
+		price = big.QuoRat(trade.BaseFilled, trade.CoinFilled)
+	}
+	base = trade.BaseFilled
+	if !base.IsGreaterThanZero() {
+		base = big.MulRat(price, trade.CoinFilled)
+	}
+	return price, base
+}
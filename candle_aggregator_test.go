@@ -0,0 +1,180 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+)
+
+func newTestTrade(id, finish int64, price, coin string) *Trade {
+	return &Trade{
+		ID:         id,
+		Pair:       PairBitcoinTether,
+		Price:      big.NewRat(price),
+		CoinFilled: big.NewRat(coin),
+		FinishTime: finish,
+	}
+}
+
+func TestCandleAggregator(t *testing.T) {
+	var (
+		agg    = NewCandleAggregator(CandleInterval1m)
+		closed []*Candle
+		late   []*Trade
+	)
+
+	agg.Lateness = 10 * time.Second
+	agg.HandleClose = func(candle *Candle) {
+		closed = append(closed, candle)
+	}
+	agg.HandleLate = func(trade *Trade) {
+		late = append(late, trade)
+	}
+
+	test.Assert(t, "Add #1", true, agg.Add(newTestTrade(1, 60, "100", "1")))
+	test.Assert(t, "Add #2", true, agg.Add(newTestTrade(2, 70, "110", "2")))
+	test.Assert(t, "Add #3", true, agg.Add(newTestTrade(3, 80, "90", "1")))
+	test.Assert(t, "Add duplicate", false,
+		agg.Add(newTestTrade(2, 70, "110", "2")))
+	test.Assert(t, "Add open order", false,
+		agg.Add(&Trade{ID: 4, Pair: PairBitcoinTether}))
+
+	// Trade on next candle, but still within lateness.
+	test.Assert(t, "Add #5", true, agg.Add(newTestTrade(5, 125, "95", "1")))
+	test.Assert(t, "len(closed)", 0, len(closed))
+
+	// Late trade within lateness still aggregated into first candle.
+	test.Assert(t, "Add #6", true, agg.Add(newTestTrade(6, 119, "105", "1")))
+
+	// This trade close the first candle.
+	test.Assert(t, "Add #7", true, agg.Add(newTestTrade(7, 130, "96", "1")))
+
+	exp := &Candle{
+		Open:        big.NewRat(100),
+		High:        big.NewRat(110),
+		Low:         big.NewRat(90),
+		Close:       big.NewRat(105),
+		VolumeBase:  big.NewRat(515),
+		VolumeCoin:  big.NewRat(5),
+		VWAP:        big.NewRat(103),
+		Pair:        PairBitcoinTether,
+		Interval:    CandleInterval1m,
+		StartTime:   60,
+		Count:       4,
+		LastTradeID: 6,
+		firstTime:   60,
+		firstID:     1,
+		lastTime:    119,
+		lastID:      6,
+	}
+	test.Assert(t, "len(closed)", 1, len(closed))
+	test.Assert(t, "closed[0]", exp, closed[0])
+
+	// Trade on closed candle is late.
+	test.Assert(t, "Add late", false, agg.Add(newTestTrade(8, 100, "1", "1")))
+	test.Assert(t, "len(late)", 1, len(late))
+
+	agg.Flush(time.Unix(200, 0))
+	test.Assert(t, "len(closed) after Flush", 2, len(closed))
+	test.Assert(t, "closed[1].StartTime", int64(120), closed[1].StartTime)
+	test.Assert(t, "closed[1].Count", int64(2), closed[1].Count)
+}
+
+func TestCandleAggregator_outOfOrder(t *testing.T) {
+	var (
+		agg    = NewCandleAggregator(CandleInterval1m)
+		closed []*Candle
+	)
+
+	agg.Lateness = time.Minute
+	agg.HandleClose = func(candle *Candle) {
+		closed = append(closed, candle)
+	}
+
+	type testCase struct {
+		trade *Trade
+		desc  string
+		exp   bool
+	}
+
+	cases := []testCase{{
+		desc:  "first added",
+		trade: newTestTrade(2, 70, "110", "1"),
+		exp:   true,
+	}, {
+		desc:  "earlier trade become Open",
+		trade: newTestTrade(1, 65, "100", "1"),
+		exp:   true,
+	}, {
+		desc:  "same time with greater ID become Close",
+		trade: newTestTrade(4, 70, "120", "1"),
+		exp:   true,
+	}, {
+		desc:  "same time with lower ID does not change Close",
+		trade: newTestTrade(3, 70, "115", "1"),
+		exp:   true,
+	}, {
+		desc: "trade without price is ignored",
+		trade: &Trade{
+			ID:         5,
+			Pair:       PairBitcoinTether,
+			CoinFilled: big.NewRat(1),
+			FinishTime: 90,
+		},
+	}}
+
+	for _, c := range cases {
+		test.Assert(t, c.desc, c.exp, agg.Add(c.trade))
+	}
+
+	agg.Flush(time.Unix(300, 0))
+
+	test.Assert(t, "len(closed)", 1, len(closed))
+	test.Assert(t, "Open", "100", closed[0].Open.String())
+	test.Assert(t, "Close", "120", closed[0].Close.String())
+	test.Assert(t, "High", "120", closed[0].High.String())
+	test.Assert(t, "Low", "100", closed[0].Low.String())
+	test.Assert(t, "Count", int64(4), closed[0].Count)
+}
+
+func TestCandleAggregator_lateOnOneInterval(t *testing.T) {
+	var (
+		agg    = NewCandleAggregator(CandleInterval1m, CandleInterval5m)
+		closed []*Candle
+		late   []*Trade
+	)
+
+	agg.HandleClose = func(candle *Candle) {
+		closed = append(closed, candle)
+	}
+	agg.HandleLate = func(trade *Trade) {
+		late = append(late, trade)
+	}
+
+	test.Assert(t, "Add #1", true, agg.Add(newTestTrade(1, 60, "100", "1")))
+
+	// This trade close the 1m candle at 60, but not the 5m candle at 0.
+	test.Assert(t, "Add #2", true, agg.Add(newTestTrade(2, 130, "110", "1")))
+	test.Assert(t, "len(closed)", 1, len(closed))
+
+	// The trade is late on 1m, so it is not aggregated into 5m too.
+	test.Assert(t, "Add late", false, agg.Add(newTestTrade(3, 100, "90", "1")))
+	test.Assert(t, "len(late)", 1, len(late))
+
+	agg.Flush(time.Unix(600, 0))
+
+	test.Assert(t, "len(closed) after Flush", 3, len(closed))
+	for _, candle := range closed {
+		if candle.Interval != CandleInterval5m {
+			continue
+		}
+		test.Assert(t, "5m Count", int64(2), candle.Count)
+		test.Assert(t, "5m Low", "100", candle.Low.String())
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CandleBackfill build the candles history for a pair from the completed
+// trades in MarketTrades, and store it into CandleFile.
+//
+// The trades are fetched page by page, from the latest one, until the
+// trade that finished before the end of last candle in file, or before
+// Since if the file is empty.
+// Only the completed candle, the one that end before the current time and
+// contains all of its trades, is stored, so the next Run resume from the
+// last stored candle without gap.
+type CandleBackfill struct {
+	api MarketDataAPI
+
+	Pair string
+
+	// Interval of candle.
+	Interval time.Duration
+
+	// Since define the Unix time in seconds of the oldest trade to be
+	// fetched when the file is empty.
+	// If its not aligned with Interval, the candle that contains it is
+	// not stored, since its trades before Since are not fetched.
+	// If its zero, all trades are fetched until MaxPages.
+	Since int64
+
+	// Limit define the number of trades fetched per request.
+	// Default to DefaultLimit.
+	Limit int64
+
+	// MaxPages define the maximum number of requests on each Run.
+	// If the file is empty and the trades at Since is not reached, the
+	// oldest candle is not stored, since it may not contains all of its
+	// trades.
+	// If the file is not empty and the end of last candle is not reached,
+	// Run return an error without storing the candles, to prevent gap in
+	// the file.
+	// Zero means no limit.
+	MaxPages int
+}
+
+// NewCandleBackfill create new backfill for the pair and interval using
+// the api, for example the REST Client.
+func NewCandleBackfill(api MarketDataAPI, pair string, interval time.Duration) (
+	bf *CandleBackfill,
+) {
+	bf = &CandleBackfill{
+		api:      api,
+		Pair:     pair,
+		Interval: interval,
+	}
+	return bf
+}
+
+// Run fetch the trades after the last candle in cf, aggregate it into
+// candles, and append the completed candles into cf.
+// It return the list of new candles.
+func (bf *CandleBackfill) Run(cf *CandleFile) (candles []*Candle, err error) {
+	logp := "CandleBackfill.Run"
+
+	if len(bf.Pair) == 0 {
+		return nil, fmt.Errorf("%s: %w", logp, ErrInvalidPair)
+	}
+	if bf.Interval < time.Second {
+		return nil, fmt.Errorf("%s: invalid interval %s", logp, bf.Interval)
+	}
+
+	last, err := cf.Last()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+
+	from := bf.Since
+	if last != nil {
+		from = last.EndTime()
+	}
+
+	trades, isComplete, err := bf.fetch(from)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	if !isComplete && last != nil {
+		return nil, fmt.Errorf("%s: trades before %d not reached after %d pages",
+			logp, from, bf.MaxPages)
+	}
+
+	// The candle that start before from, or that contains the oldest
+	// trade when the paging stopped early, may not contains all of its
+	// trades.
+	minStart := from
+	if !isComplete && len(trades) > 0 {
+		minStart = candleStart(trades[0].FinishTime, bf.Interval) + 1
+	}
+
+	agg := NewCandleAggregator(bf.Interval)
+	agg.HandleClose = func(candle *Candle) {
+		if candle.StartTime < minStart {
+			return
+		}
+		candles = append(candles, candle)
+	}
+	for x := range trades {
+		agg.Add(&trades[x])
+	}
+	agg.Flush(time.Now())
+
+	err = cf.Append(candles)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	return candles, nil
+}
+
+// fetch the trades that finished at or after from, sorted by finish time.
+// It return isComplete as false if the paging stopped by MaxPages before
+// reaching the trade at from or the end of trades.
+func (bf *CandleBackfill) fetch(from int64) (
+	trades []Trade, isComplete bool, err error,
+) {
+	limit := bf.Limit
+	if limit <= 0 || limit > DefaultLimit {
+		limit = DefaultLimit
+	}
+
+	var offset int64
+	for page := 1; bf.MaxPages <= 0 || page <= bf.MaxPages; page++ {
+		mtrades, err := bf.api.MarketTrades(bf.Pair, offset, limit)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var (
+			isEnd = int64(len(mtrades.Asks)) < limit &&
+				int64(len(mtrades.Bids)) < limit
+		)
+		for _, list := range [][]Trade{mtrades.Asks, mtrades.Bids} {
+			for _, trade := range list {
+				if trade.FinishTime < from {
+					isEnd = true
+					continue
+				}
+				if len(trade.Pair) == 0 {
+					trade.Pair = bf.Pair
+				}
+				trades = append(trades, trade)
+			}
+		}
+		if isEnd {
+			isComplete = true
+			break
+		}
+		offset += limit
+	}
+
+	sort.SliceStable(trades, func(x, y int) bool {
+		if trades[x].FinishTime == trades[y].FinishTime {
+			return trades[x].ID < trades[y].ID
+		}
+		return trades[x].FinishTime < trades[y].FinishTime
+	})
+	return trades, isComplete, nil
+}
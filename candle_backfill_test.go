@@ -0,0 +1,166 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+)
+
+// tradesStub implement MarketDataAPI that return the page of history as
+// the Bids of MarketTrades.
+type tradesStub struct {
+	MarketDataAPI
+
+	// history contains the trades sorted from the latest one.
+	history []Trade
+}
+
+func (stub *tradesStub) MarketTrades(pair string, offset, limit int64) (
+	*MarketTrades, error,
+) {
+	mtrades := &MarketTrades{}
+	for x := offset; x < offset+limit; x++ {
+		if x >= int64(len(stub.history)) {
+			break
+		}
+		mtrades.Bids = append(mtrades.Bids, stub.history[x])
+	}
+	return mtrades, nil
+}
+
+func newTestCandleFile(t *testing.T) *CandleFile {
+	return NewCandleFile(filepath.Join(t.TempDir(), "btc_usdt_1m.jsonl"))
+}
+
+func TestCandleBackfill_Run(t *testing.T) {
+	var (
+		api = &tradesStub{
+			history: []Trade{
+				*newTestTrade(5, 130, "104", "1"),
+				*newTestTrade(4, 125, "103", "1"),
+				*newTestTrade(3, 70, "102", "1"),
+				*newTestTrade(2, 65, "101", "1"),
+				*newTestTrade(1, 10, "100", "1"),
+			},
+		}
+		cf = newTestCandleFile(t)
+	)
+
+	bf := NewCandleBackfill(api, PairBitcoinTether, CandleInterval1m)
+	bf.Since = 60
+	bf.Limit = 2
+
+	candles, err := bf.Run(cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Assert(t, "len(candles)", 2, len(candles))
+	test.Assert(t, "candles[0].StartTime", int64(60), candles[0].StartTime)
+	test.Assert(t, "candles[0].Close", big.NewRat(102), candles[0].Close)
+	test.Assert(t, "candles[1].StartTime", int64(120), candles[1].StartTime)
+
+	// The second run only fetch the new trades.
+	api.history = append([]Trade{
+		*newTestTrade(7, 200, "106", "1"),
+		*newTestTrade(6, 190, "105", "1"),
+	}, api.history...)
+
+	candles, err = bf.Run(cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "len(candles) on resume", 1, len(candles))
+	test.Assert(t, "candles[0].StartTime", int64(180), candles[0].StartTime)
+	test.Assert(t, "candles[0].Count", int64(2), candles[0].Count)
+
+	got, err := cf.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "len(ReadAll)", 3, len(got))
+	test.Assert(t, "ReadAll[1]", candles[0].StartTime-60, got[1].StartTime)
+}
+
+func TestCandleBackfill_Run_incomplete(t *testing.T) {
+	api := &tradesStub{
+		history: []Trade{
+			*newTestTrade(6, 190, "105", "1"),
+			*newTestTrade(5, 130, "104", "1"),
+			*newTestTrade(4, 125, "103", "1"),
+			*newTestTrade(3, 70, "102", "1"),
+			*newTestTrade(2, 65, "101", "1"),
+		},
+	}
+
+	type testCase struct {
+		desc     string
+		expStart []int64
+		since    int64
+		maxPages int
+	}
+
+	cases := []testCase{{
+		desc: "MaxPages stop in the middle of candle 60",
+		// The trades on page 1 and 2 are 190, 130, 125, and 70, so
+		// the candle 60 miss the trade at 65.
+		maxPages: 2,
+		expStart: []int64{120, 180},
+	}, {
+		desc:     "Since is not aligned with interval",
+		since:    66,
+		expStart: []int64{120, 180},
+	}, {
+		desc:     "Since is aligned with interval",
+		since:    60,
+		expStart: []int64{60, 120, 180},
+	}}
+
+	for _, c := range cases {
+		bf := NewCandleBackfill(api, PairBitcoinTether, CandleInterval1m)
+		bf.Since = c.since
+		bf.Limit = 2
+		bf.MaxPages = c.maxPages
+
+		candles, err := bf.Run(newTestCandleFile(t))
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+
+		var gotStart []int64
+		for _, candle := range candles {
+			gotStart = append(gotStart, candle.StartTime)
+		}
+		test.Assert(t, c.desc, c.expStart, gotStart)
+	}
+
+	// The file that is not empty is not resumed if the paging does not
+	// reach the last candle.
+	cf := newTestCandleFile(t)
+	err := cf.Append([]*Candle{newCandle(PairBitcoinTether,
+		CandleInterval1m, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bf := NewCandleBackfill(api, PairBitcoinTether, CandleInterval1m)
+	bf.Limit = 2
+	bf.MaxPages = 1
+
+	_, err = bf.Run(cf)
+	if err == nil {
+		t.Fatal("Run: want error on gap, got nil")
+	}
+
+	got, err := cf.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "len(ReadAll) after gap", 1, len(got))
+}
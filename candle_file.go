@@ -0,0 +1,117 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// CandleFile store the candles in a file using JSON Lines format, one
+// Candle per line, ordered by start time.
+// Each file should contains the candles for single pair and interval.
+type CandleFile struct {
+	Path string
+}
+
+// NewCandleFile create new CandleFile on path.
+// The file is created on the first Append.
+func NewCandleFile(path string) (cf *CandleFile) {
+	cf = &CandleFile{
+		Path: path,
+	}
+	return cf
+}
+
+// Append write the candles at the end of file.
+func (cf *CandleFile) Append(candles []*Candle) (err error) {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	logp := "CandleFile.Append"
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, candle := range candles {
+		err = enc.Encode(candle)
+		if err != nil {
+			return fmt.Errorf("%s: %w", logp, err)
+		}
+	}
+
+	f, err := os.OpenFile(cf.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("%s: %w", logp, err)
+	}
+
+	_, err = f.Write(buf.Bytes())
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%s: %w", logp, err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", logp, err)
+	}
+	return nil
+}
+
+// Last return the last candle in the file.
+// It return nil without error if the file is not exist or empty.
+func (cf *CandleFile) Last() (last *Candle, err error) {
+	candles, err := cf.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, nil
+	}
+	return candles[len(candles)-1], nil
+}
+
+// ReadAll read all candles from file.
+// It return nil without error if the file is not exist.
+func (cf *CandleFile) ReadAll() (candles []*Candle, err error) {
+	logp := "CandleFile.ReadAll"
+
+	f, err := os.Open(cf.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	defer f.Close()
+
+	var (
+		scanner = bufio.NewScanner(f)
+		n       int
+	)
+	for scanner.Scan() {
+		n++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		candle := &Candle{}
+		err = json.Unmarshal(line, candle)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", logp, n, err)
+		}
+		candles = append(candles, candle)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	return candles, nil
+}
@@ -10,10 +10,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 
 	libhttp "github.com/shuLhan/share/lib/http"
-	"github.com/shuLhan/share/lib/math/big"
 )
 
 // Client for CAMP REST API v2.
@@ -102,7 +100,7 @@ func (cl *Client) MarketInfo() (marketInfos []MarketInfo, err error) {
 
 	marketInfos = make([]MarketInfo, 0)
 	res := &Response{
-		Data: marketInfos,
+		Data: &marketInfos,
 	}
 
 	err = json.Unmarshal(resBody, res)
@@ -266,39 +264,9 @@ func (cl *Client) UserInfo() (user *User, err error) {
 //
 // This method require authentication.
 func (cl *Client) UserTrades(tp ListTradeParams) (trades []Trade, err error) {
-	params := url.Values{
-		ParamNamePair: []string{tp.Pair},
-	}
-	if tp.Offset > 0 {
-		params.Set(ParamNameOffset, strconv.FormatInt(tp.Offset, 10))
-	}
-	if tp.Limit <= 0 && tp.Limit > DefaultLimit {
-		tp.Limit = DefaultLimit
-	}
-
-	if len(tp.Sort) == 0 {
-		tp.Sort = SortDescending
-	} else {
-		tp.Sort = strings.ToLower(tp.Sort)
-	}
-	switch tp.Sort {
-	case SortAscending, SortDescending:
-	default:
-		return nil, fmt.Errorf("UserTrades: unknown sort order: %q", tp.Sort)
-	}
-
-	params.Set(ParamNameLimit, strconv.FormatInt(tp.Limit, 10))
-	if tp.IDAfter > 0 {
-		params.Set(ParamNameIDAfter, strconv.FormatInt(tp.IDAfter, 10))
-	}
-	if tp.IDBefore > 0 {
-		params.Set(ParamNameIDBefore, strconv.FormatInt(tp.IDBefore, 10))
-	}
-	if tp.TimeAfter > 0 {
-		params.Set(ParamNameTimeAfter, strconv.FormatInt(tp.TimeAfter, 10))
-	}
-	if tp.TimeBefore > 0 {
-		params.Set(ParamNameTimeBefore, strconv.FormatInt(tp.TimeBefore, 10))
+	params, _, err := tp.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("UserTrades: %w", err)
 	}
 
 	b, err := cl.doSecureRequest(http.MethodGet, APIUserTrades, params)
@@ -462,31 +430,16 @@ func (cl *Client) UserTransactions(asset string, limit int64) (trans *AssetTrans
 // If all the data is correct, the callback URL should return HTTP response
 // 200 with string “ok” (without quotes), and we will process the withdrawn in
 // our system, otherwise the request will be fail.
-func (cl *Client) UserWithdraw(
-	requestID, asset, network, address, addressType, memo string,
-	amount *big.Rat,
-) (withdraw *WithdrawItem, err error) {
-	if len(requestID) == 0 {
-		return nil, ErrInvalidRequestID
-	}
-	if len(asset) == 0 {
-		return nil, ErrInvalidAsset
-	}
-	if len(address) == 0 {
-		return nil, ErrWalletAddress
-	}
-	if amount == nil || amount.IsLessOrEqual(0) {
-		return nil, ErrInvalidAmount
+func (cl *Client) UserWithdraw(wreq *WithdrawRequest) (
+	withdraw *WithdrawItem, err error,
+) {
+	if wreq == nil {
+		return nil, nil
 	}
 
-	params := url.Values{
-		ParamNameRequestID:   []string{requestID},
-		ParamNameAsset:       []string{asset},
-		ParamNameNetwork:     []string{network},
-		ParamNameAddress:     []string{address},
-		ParamNameAddressType: []string{addressType},
-		ParamNameMemo:        []string{memo},
-		ParamNameAmount:      []string{amount.String()},
+	params, _, err := wreq.Pack()
+	if err != nil {
+		return nil, err
 	}
 
 	b, err := cl.doSecureRequest(http.MethodPost, APIUserWithdraw,
@@ -0,0 +1,357 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// List of compile time checks for API interfaces.
+var (
+	_ MarketDataAPI = (*Client)(nil)
+	_ MarketDataAPI = (*WebSocketPublic)(nil)
+	_ MarketDataAPI = (*FailoverClient)(nil)
+
+	_ AccountAPI = (*Client)(nil)
+	_ AccountAPI = (*WebSocketPrivate)(nil)
+	_ AccountAPI = (*FailoverClient)(nil)
+
+	_ TradingAPI = (*Client)(nil)
+	_ TradingAPI = (*WebSocketPrivate)(nil)
+	_ TradingAPI = (*FailoverClient)(nil)
+)
+
+// FailoverClient is a client that implements MarketDataAPI, AccountAPI, and
+// TradingAPI using WebSocket connection if its connected, and fail over to
+// REST Client if its not.
+//
+// The public market APIs are send through WebSocketPublic and the private
+// APIs are send through WebSocketPrivate.
+// If one of the WebSocket client is nil, all of its APIs are send through
+// REST Client.
+//
+// The request is send using REST Client if the WebSocket is not connected
+// or the request cannot be send because the connection has been closed.
+// For request that only read data, for example MarketDepths and UserInfo,
+// the request is also retried using REST Client if the WebSocket connection
+// lost before receiving response (ErrWebSocketDisconnected) or timeout
+// (ErrWebSocketTimeout).
+// Any errors returned by server are not retried.
+type FailoverClient struct {
+	rest    restAPI
+	public  publicAPI
+	private privateAPI
+}
+
+// restAPI define the APIs used by FailoverClient as fallback, implemented
+// by Client.
+type restAPI interface {
+	MarketDataAPI
+	AccountAPI
+	TradingAPI
+}
+
+// publicAPI define the APIs used by FailoverClient for public market,
+// implemented by WebSocketPublic.
+type publicAPI interface {
+	MarketDataAPI
+	IsConnected() bool
+}
+
+// privateAPI define the APIs used by FailoverClient for private account
+// and trading, implemented by WebSocketPrivate.
+type privateAPI interface {
+	AccountAPI
+	TradingAPI
+	IsConnected() bool
+}
+
+// NewFailoverClient create new client that use the WebSocket public and
+// private as the primary transport and the REST client as fallback.
+// The rest parameter is required, while the public and private parameters
+// are optional.
+func NewFailoverClient(
+	rest *Client, public *WebSocketPublic, private *WebSocketPrivate,
+) (fc *FailoverClient) {
+	fc = &FailoverClient{
+		rest: rest,
+	}
+	// Assign the non-nil client only, so the nil pointer does not
+	// become non-nil interface.
+	if public != nil {
+		fc.public = public
+	}
+	if private != nil {
+		fc.private = private
+	}
+	return fc
+}
+
+// MarketDepths fetch list of market's depth for specific pair.
+func (fc *FailoverClient) MarketDepths(pair string) (*MarketDepths, error) {
+	if fc.isPublicUp() {
+		depths, err := fc.public.MarketDepths(pair)
+		if !isFailoverRead(err) {
+			return depths, err
+		}
+	}
+	return fc.rest.MarketDepths(pair)
+}
+
+// MarketInfo return information about all the pair in the platform.
+func (fc *FailoverClient) MarketInfo() ([]MarketInfo, error) {
+	if fc.isPublicUp() {
+		infos, err := fc.public.MarketInfo()
+		if !isFailoverRead(err) {
+			return infos, err
+		}
+	}
+	return fc.rest.MarketInfo()
+}
+
+// MarketPrices return list of all latest pair's prices.
+func (fc *FailoverClient) MarketPrices() (MarketPrices, error) {
+	if fc.isPublicUp() {
+		prices, err := fc.public.MarketPrices()
+		if !isFailoverRead(err) {
+			return prices, err
+		}
+	}
+	return fc.rest.MarketPrices()
+}
+
+// MarketSummaries return the summaries (ticker) of all pairs.
+func (fc *FailoverClient) MarketSummaries() (*MarketSummaries, error) {
+	if fc.isPublicUp() {
+		summaries, err := fc.public.MarketSummaries()
+		if !isFailoverRead(err) {
+			return summaries, err
+		}
+	}
+	return fc.rest.MarketSummaries()
+}
+
+// MarketTicker return the ticker information on specific pair.
+func (fc *FailoverClient) MarketTicker(pair string) (*MarketTicker, error) {
+	if fc.isPublicUp() {
+		tick, err := fc.public.MarketTicker(pair)
+		if !isFailoverRead(err) {
+			return tick, err
+		}
+	}
+	return fc.rest.MarketTicker(pair)
+}
+
+// MarketTrades return list of all completed trades in the market, specific to
+// pair, grouped by ask and bid.
+func (fc *FailoverClient) MarketTrades(pair string, offset, limit int64) (
+	*MarketTrades, error,
+) {
+	if fc.isPublicUp() {
+		trades, err := fc.public.MarketTrades(pair, offset, limit)
+		if !isFailoverRead(err) {
+			return trades, err
+		}
+	}
+	return fc.rest.MarketTrades(pair, offset, limit)
+}
+
+// MarketTradesOpen return list of all open trades in the market, specific to
+// pair's name, grouped by ask and bid.
+func (fc *FailoverClient) MarketTradesOpen(pair string) (*TradesOpen, error) {
+	if fc.isPublicUp() {
+		openTrades, err := fc.public.MarketTradesOpen(pair)
+		if !isFailoverRead(err) {
+			return openTrades, err
+		}
+	}
+	return fc.rest.MarketTradesOpen(pair)
+}
+
+// UserInfo fetch the user information and balances.
+func (fc *FailoverClient) UserInfo() (*User, error) {
+	if fc.isPrivateUp() {
+		user, err := fc.private.UserInfo()
+		if !isFailoverRead(err) {
+			return user, err
+		}
+	}
+	return fc.rest.UserInfo()
+}
+
+// UserOrderInfo fetch a single user's trade information based on pair's name
+// and trade ID.
+func (fc *FailoverClient) UserOrderInfo(pair string, id int64) (*Trade, error) {
+	if fc.isPrivateUp() {
+		trade, err := fc.private.UserOrderInfo(pair, id)
+		if !isFailoverRead(err) {
+			return trade, err
+		}
+	}
+	return fc.rest.UserOrderInfo(pair, id)
+}
+
+// UserOrdersClosed fetch the user closed orders based on pair's name and
+// range of submit time.
+func (fc *FailoverClient) UserOrdersClosed(pair string, timeAfter, timeBefore int64) (
+	[]Trade, error,
+) {
+	if fc.isPrivateUp() {
+		trades, err := fc.private.UserOrdersClosed(pair, timeAfter, timeBefore)
+		if !isFailoverRead(err) {
+			return trades, err
+		}
+	}
+	return fc.rest.UserOrdersClosed(pair, timeAfter, timeBefore)
+}
+
+// UserOrdersOpen fetch the user open orders based on pair's name.
+func (fc *FailoverClient) UserOrdersOpen(pair string) (PairTradesOpen, error) {
+	if fc.isPrivateUp() {
+		pairTradesOpen, err := fc.private.UserOrdersOpen(pair)
+		if !isFailoverRead(err) {
+			return pairTradesOpen, err
+		}
+	}
+	return fc.rest.UserOrdersOpen(pair)
+}
+
+// UserTrades list the user's trade history.
+func (fc *FailoverClient) UserTrades(tp ListTradeParams) ([]Trade, error) {
+	if fc.isPrivateUp() {
+		trades, err := fc.private.UserTrades(tp)
+		if !isFailoverRead(err) {
+			return trades, err
+		}
+	}
+	return fc.rest.UserTrades(tp)
+}
+
+// UserTransactions fetch all user deposit and withdraw transaction history.
+func (fc *FailoverClient) UserTransactions(asset string, limit int64) (
+	*AssetTransactions, error,
+) {
+	if fc.isPrivateUp() {
+		trans, err := fc.private.UserTransactions(asset, limit)
+		if !isFailoverRead(err) {
+			return trans, err
+		}
+	}
+	return fc.rest.UserTransactions(asset, limit)
+}
+
+// UserWithdraw withdraw your assets into another address.
+func (fc *FailoverClient) UserWithdraw(wreq *WithdrawRequest) (*WithdrawItem, error) {
+	if fc.isPrivateUp() {
+		withdraw, err := fc.private.UserWithdraw(wreq)
+		if !isFailover(err) {
+			return withdraw, err
+		}
+	}
+	return fc.rest.UserWithdraw(wreq)
+}
+
+// TradeAsk request to sell the coin on market.
+func (fc *FailoverClient) TradeAsk(treq *TradeRequest) (*TradeResponse, error) {
+	if fc.isPrivateUp() {
+		tres, err := fc.private.TradeAsk(treq)
+		if !isFailover(err) {
+			return tres, err
+		}
+	}
+	return fc.rest.TradeAsk(treq)
+}
+
+// TradeBid request to buy the coin on market.
+func (fc *FailoverClient) TradeBid(treq *TradeRequest) (*TradeResponse, error) {
+	if fc.isPrivateUp() {
+		tres, err := fc.private.TradeBid(treq)
+		if !isFailover(err) {
+			return tres, err
+		}
+	}
+	return fc.rest.TradeBid(treq)
+}
+
+// TradeBulk request trade with multiple orders and/or cancellation.
+func (fc *FailoverClient) TradeBulk(tbReq *TradeBulk) (*TradeBulk, error) {
+	if fc.isPrivateUp() {
+		tbRes, err := fc.private.TradeBulk(tbReq)
+		if !isFailover(err) {
+			return tbRes, err
+		}
+	}
+	return fc.rest.TradeBulk(tbReq)
+}
+
+// TradeCancel cancel the open trade using ID and pair information in Trade.
+func (fc *FailoverClient) TradeCancel(trade *Trade) (*Trade, error) {
+	if fc.isPrivateUp() {
+		canceled, err := fc.private.TradeCancel(trade)
+		if !isFailover(err) {
+			return canceled, err
+		}
+	}
+	return fc.rest.TradeCancel(trade)
+}
+
+// TradeCancelAll cancel all user's open ask and bid orders.
+func (fc *FailoverClient) TradeCancelAll() ([]Trade, error) {
+	if fc.isPrivateUp() {
+		canceled, err := fc.private.TradeCancelAll()
+		if !isFailover(err) {
+			return canceled, err
+		}
+	}
+	return fc.rest.TradeCancelAll()
+}
+
+// TradeCancelAsk cancel the specific open sell by pair and ID.
+func (fc *FailoverClient) TradeCancelAsk(pair string, id int64) (*TradeResponse, error) {
+	if fc.isPrivateUp() {
+		tres, err := fc.private.TradeCancelAsk(pair, id)
+		if !isFailover(err) {
+			return tres, err
+		}
+	}
+	return fc.rest.TradeCancelAsk(pair, id)
+}
+
+// TradeCancelBid cancel the specific open buy by pair and ID.
+func (fc *FailoverClient) TradeCancelBid(pair string, id int64) (*TradeResponse, error) {
+	if fc.isPrivateUp() {
+		tres, err := fc.private.TradeCancelBid(pair, id)
+		if !isFailover(err) {
+			return tres, err
+		}
+	}
+	return fc.rest.TradeCancelBid(pair, id)
+}
+
+func (fc *FailoverClient) isPublicUp() bool {
+	return fc.public != nil && fc.public.IsConnected()
+}
+
+func (fc *FailoverClient) isPrivateUp() bool {
+	return fc.private != nil && fc.private.IsConnected()
+}
+
+// isFailover return true if the request should be retried using REST
+// client.
+// Only error where the request is never received by server can be retried,
+// to prevent the same order placed twice.
+func isFailover(err error) bool {
+	return errors.Is(err, websocket.ErrConnClosed)
+}
+
+// isFailoverRead return true if the read only request should be retried
+// using REST client.
+func isFailoverRead(err error) bool {
+	return isFailover(err) ||
+		errors.Is(err, ErrWebSocketDisconnected) ||
+		errors.Is(err, ErrWebSocketTimeout)
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// apiStub implement the restAPI, publicAPI, and privateAPI that count the
+// number of calls and return the err.
+type apiStub struct {
+	restAPI
+
+	err         error
+	calls       int
+	isConnected bool
+}
+
+func (stub *apiStub) IsConnected() bool {
+	return stub.isConnected
+}
+
+func (stub *apiStub) MarketDepths(pair string) (*MarketDepths, error) {
+	stub.calls++
+	return nil, stub.err
+}
+
+func (stub *apiStub) UserInfo() (*User, error) {
+	stub.calls++
+	return nil, stub.err
+}
+
+func (stub *apiStub) TradeBid(treq *TradeRequest) (*TradeResponse, error) {
+	stub.calls++
+	return nil, stub.err
+}
+
+func TestFailoverClient(t *testing.T) {
+	type testCase struct {
+		wsErr error
+		desc  string
+
+		// isConnected set the WebSocket state.
+		isConnected bool
+
+		// expReadRest is true if the read request is send through
+		// REST.
+		expReadRest bool
+
+		// expWriteRest is true if the write request is send through
+		// REST.
+		expWriteRest bool
+	}
+
+	cases := []testCase{{
+		desc:        "success",
+		isConnected: true,
+	}, {
+		desc:         "not connected",
+		expReadRest:  true,
+		expWriteRest: true,
+	}, {
+		desc:         "connection closed before send",
+		isConnected:  true,
+		wsErr:        fmt.Errorf("send: %w", websocket.ErrConnClosed),
+		expReadRest:  true,
+		expWriteRest: true,
+	}, {
+		desc:        "disconnected before response",
+		isConnected: true,
+		wsErr:       ErrWebSocketDisconnected,
+		expReadRest: true,
+	}, {
+		desc:        "timeout",
+		isConnected: true,
+		wsErr:       ErrWebSocketTimeout,
+		expReadRest: true,
+	}, {
+		desc:        "error from server",
+		isConnected: true,
+		wsErr:       ErrInvalidPair,
+	}}
+
+	type apiCall struct {
+		call    func(fc *FailoverClient) error
+		ws      func() *apiStub
+		desc    string
+		isWrite bool
+	}
+
+	var (
+		rest    *apiStub
+		public  *apiStub
+		private *apiStub
+	)
+
+	calls := []apiCall{{
+		desc: "MarketDepths",
+		call: func(fc *FailoverClient) error {
+			_, err := fc.MarketDepths(PairBitcoinTether)
+			return err
+		},
+		ws: func() *apiStub { return public },
+	}, {
+		desc: "UserInfo",
+		call: func(fc *FailoverClient) error {
+			_, err := fc.UserInfo()
+			return err
+		},
+		ws: func() *apiStub { return private },
+	}, {
+		desc: "TradeBid",
+		call: func(fc *FailoverClient) error {
+			_, err := fc.TradeBid(&TradeRequest{})
+			return err
+		},
+		ws:      func() *apiStub { return private },
+		isWrite: true,
+	}}
+
+	for _, c := range cases {
+		for _, apic := range calls {
+			rest = &apiStub{}
+			public = &apiStub{
+				err:         c.wsErr,
+				isConnected: c.isConnected,
+			}
+			private = &apiStub{
+				err:         c.wsErr,
+				isConnected: c.isConnected,
+			}
+
+			fc := &FailoverClient{
+				rest:    rest,
+				public:  public,
+				private: private,
+			}
+
+			var (
+				desc    = c.desc + ": " + apic.desc
+				isRest  = c.expReadRest
+				expErr  = c.wsErr
+				expWS   = 1
+				expRest = 0
+			)
+			if apic.isWrite {
+				isRest = c.expWriteRest
+			}
+			if !c.isConnected {
+				expWS = 0
+			}
+			if isRest {
+				expErr = nil
+				expRest = 1
+			}
+
+			err := apic.call(fc)
+			test.Assert(t, desc+": error", expErr, err)
+			test.Assert(t, desc+": WebSocket calls", expWS,
+				apic.ws().calls)
+			test.Assert(t, desc+": REST calls", expRest, rest.calls)
+		}
+	}
+}
+
+func TestNewFailoverClient(t *testing.T) {
+	rest := &apiStub{}
+
+	// The nil WebSocket clients send all requests through REST.
+	fc := NewFailoverClient(nil, nil, nil)
+	fc.rest = rest
+
+	_, err := fc.MarketDepths(PairBitcoinTether)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fc.TradeBid(&TradeRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "REST calls", 2, rest.calls)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// HubFilter define the topic and pairs that a subscriber in
+// MarketDataHub want to receive.
+type HubFilter struct {
+	// Topic is the name of topic, one of TopicDepths, TopicSummaries,
+	// TopicTicker, or TopicTrades.
+	Topic string
+
+	// Pairs is the list of pair names to be received.
+	// Its required for all topics except TopicSummaries.
+	Pairs []string
+}
+
+// isMatch return true if the message topic and pair match with the
+// filter.
+func (filter *HubFilter) isMatch(msg *HubMessage) bool {
+	if filter.Topic != msg.Topic {
+		return false
+	}
+	if filter.Topic == TopicSummaries {
+		return true
+	}
+	for _, pair := range filter.Pairs {
+		if pair == msg.Pair {
+			return true
+		}
+	}
+	return false
+}
+
+// validate the filter.
+func (filter *HubFilter) validate() error {
+	switch filter.Topic {
+	case TopicSummaries:
+		return nil
+	case TopicDepths, TopicTicker, TopicTrades:
+		if len(filter.Pairs) == 0 {
+			return ErrInvalidPair
+		}
+		for _, pair := range filter.Pairs {
+			if len(pair) == 0 {
+				return ErrInvalidPair
+			}
+		}
+		return nil
+	}
+	return ErrInvalidTopic
+}
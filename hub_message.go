@@ -0,0 +1,52 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "strconv"
+
+// HubMessage contains the broadcast message delivered by MarketDataHub to
+// its subscribers.
+// Only one of the Depths, Ticker, Trade, or Summaries is set, based on the
+// Topic.
+//
+// The same message is shared by all subscribers, so it must not be
+// modified.
+type HubMessage struct {
+	Depths    *MarketDepths
+	Ticker    *MarketTicker
+	Trade     *Trade
+	Summaries *MarketSummaries
+
+	// Topic is the name of topic where the message come from.
+	Topic string
+
+	// Pair is the name of pair in the message.
+	// Its empty for TopicSummaries.
+	Pair string
+}
+
+// key return the key of message for QueuePolicyCoalesce.
+func (msg *HubMessage) key() string {
+	if msg.Trade != nil {
+		return msg.Topic + ":" + strconv.FormatInt(msg.Trade.ID, 10)
+	}
+	return msg.Topic + ":" + msg.Pair
+}
+
+// coalesce return the message that combine msg and the next message with
+// the same key for QueuePolicyCoalesce.
+// For TopicDepths, the levels are combined using coalesceDepths into new
+// message, otherwise the next message is returned.
+func (msg *HubMessage) coalesce(next *HubMessage) *HubMessage {
+	if msg.Depths == nil || next.Depths == nil {
+		return next
+	}
+	var (
+		depths = coalesceDepths(*msg.Depths, *next.Depths)
+		merged = *next
+	)
+	merged.Depths = &depths
+	return &merged
+}
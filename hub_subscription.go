@@ -0,0 +1,53 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "sync/atomic"
+
+// HubSubscription is the subscriber in MarketDataHub.
+// Each subscriber has its own queue, so the slow subscriber does not
+// block the other subscribers.
+type HubSubscription struct {
+	hub   *MarketDataHub
+	queue *topicQueue[*HubMessage]
+
+	// chClosed is closed when the subscription is closed.
+	chClosed chan struct{}
+
+	// C is the channel that receive the messages that match with the
+	// filter.
+	// The channel is never closed.
+	C <-chan *HubMessage
+
+	Filter HubFilter
+
+	id       int64
+	isClosed atomic.Bool
+}
+
+// Close remove the subscription from the hub.
+// The server subscription on the pairs that are not used by other
+// subscribers will be unsubscribed.
+func (sub *HubSubscription) Close() error {
+	if !sub.stop() {
+		return nil
+	}
+	return sub.hub.remove(sub)
+}
+
+// Stats return the counters of subscription queue.
+func (sub *HubSubscription) Stats() QueueStats {
+	return sub.queue.stats()
+}
+
+// stop the subscription queue.
+// It return true if the subscription is not closed before.
+func (sub *HubSubscription) stop() bool {
+	if sub.isClosed.Swap(true) {
+		return false
+	}
+	close(sub.chClosed)
+	return true
+}
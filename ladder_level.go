@@ -0,0 +1,16 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "github.com/shuLhan/share/lib/math/big"
+
+// LadderLevel define single price level in the OrderLadder.
+type LadderLevel struct {
+	// Price of the limit order at this level.
+	Price *big.Rat
+
+	// Amount of coin to be placed at this level.
+	Amount *big.Rat
+}
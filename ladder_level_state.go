@@ -0,0 +1,34 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import liberrors "github.com/shuLhan/share/lib/errors"
+
+// LadderLevelState contains the state of single LadderLevel after
+// reconciliation.
+type LadderLevelState struct {
+	// Err contains the error from server if the new order for this level
+	// is failed.
+	Err *liberrors.E
+
+	LadderLevel
+
+	// Type of order, its either "buy" or "sell".
+	Type string
+
+	// OrderID is the ID of open order that represent this level in the
+	// market.
+	// Its zero if the level is not live.
+	OrderID int64
+
+	// IsKept is true if the level is represented by existing open order,
+	// and no new order is placed.
+	IsKept bool
+}
+
+// IsLive return true if the level has open order in the market.
+func (state *LadderLevelState) IsLive() bool {
+	return state.OrderID > 0
+}
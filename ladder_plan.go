@@ -0,0 +1,63 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// LadderPlan contains the minimal bulk request to move the open orders into
+// the desired OrderLadder.
+type LadderPlan struct {
+	// Bulk contains new orders and cancellation to be send to server.
+	// The Orders and Cancel is empty if the open orders already match
+	// the ladder.
+	// Since the server process the orders before the cancellation, the
+	// Cancel should be send before the Orders, as in
+	// LadderReconciler.Reconcile.
+	Bulk *TradeBulk
+
+	// orders map the RefID of new order into their level state.
+	orders map[int64]*LadderLevelState
+
+	result *LadderResult
+}
+
+// IsEmpty return true if there is no order to be placed or cancelled.
+func (plan *LadderPlan) IsEmpty() bool {
+	return len(plan.Bulk.Orders) == 0 && len(plan.Bulk.Cancel) == 0
+}
+
+// Apply the per item result from TradeBulk into the ladder result.
+// It can be called several times, one for each TradeBulk request, where
+// the items that are not in tbRes are not changed.
+func (plan *LadderPlan) Apply(tbRes *TradeBulkResult) (result *LadderResult) {
+	result = plan.result
+	if tbRes == nil {
+		return result
+	}
+
+	for refID, state := range plan.orders {
+		item := tbRes.Get(refID)
+		if item == nil {
+			continue
+		}
+		if item.IsSuccess() {
+			state.OrderID = item.OrderID
+			continue
+		}
+		state.Err = item.Err
+	}
+
+	for _, req := range plan.Bulk.Cancel {
+		item := tbRes.Get(req.RefID)
+		if item == nil {
+			continue
+		}
+		if item.IsSuccess() {
+			result.Cancelled = append(result.Cancelled, req)
+			continue
+		}
+		result.CancelFailed = append(result.CancelFailed, item)
+	}
+
+	return result
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"fmt"
+
+	"github.com/shuLhan/share/lib/math/big"
+)
+
+// LadderReconciler compute and apply the minimal orders and cancellation
+// to move the user's open orders into the desired OrderLadder, using
+// TradeBulk request.
+//
+// An open order is kept if its price and remaining coin amount equal to one
+// of the desired level, otherwise it will be cancelled.
+// Each desired level that does not have matching open order will be placed
+// as new limit order.
+type LadderReconciler struct {
+	account AccountAPI
+	trading TradingAPI
+
+	// MaxBulkItems define the maximum number of items per TradeBulk
+	// request, see ExecuteTradeBulk.
+	MaxBulkItems int
+}
+
+// NewLadderReconciler create new reconciler that fetch the open orders
+// using account and send the bulk request using trading.
+// Both parameters can be the same client, for example Client or
+// WebSocketPrivate.
+func NewLadderReconciler(account AccountAPI, trading TradingAPI) (
+	lr *LadderReconciler,
+) {
+	lr = &LadderReconciler{
+		account: account,
+		trading: trading,
+	}
+	return lr
+}
+
+// Plan compute the bulk request to move the open orders into the ladder.
+//
+// It return ErrInvalidPair if the ladder Pair is empty, and
+// ErrInvalidPrice or ErrInvalidAmount if one of the level has empty,
+// zero, or negative price or amount.
+func (lr *LadderReconciler) Plan(ladder *OrderLadder, open *TradesOpen) (
+	plan *LadderPlan, err error,
+) {
+	logp := "Plan"
+
+	err = ladder.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+
+	plan = &LadderPlan{
+		Bulk: &TradeBulk{
+			Pair: ladder.Pair,
+		},
+		orders: make(map[int64]*LadderLevelState),
+		result: &LadderResult{
+			Asks: newLadderLevelStates(TradeTypeAsk, ladder.Asks),
+			Bids: newLadderLevelStates(TradeTypeBid, ladder.Bids),
+		},
+	}
+
+	if open == nil {
+		open = &TradesOpen{}
+	}
+
+	var refID int64
+
+	stale := matchLadderLevels(plan.result.Asks, open.Asks)
+	stale = append(stale, matchLadderLevels(plan.result.Bids, open.Bids)...)
+
+	for _, states := range [][]LadderLevelState{plan.result.Asks, plan.result.Bids} {
+		for x := range states {
+			state := &states[x]
+			if state.IsKept {
+				continue
+			}
+			refID++
+			item := &BulkOrderItem{
+				TradeRequest: TradeRequest{
+					Price:      state.Price,
+					Amount:     state.Amount,
+					Type:       state.Type,
+					Method:     TradeMethodLimit,
+					Pair:       ladder.Pair,
+					IsPostOnly: ladder.IsPostOnly,
+				},
+				RefID: refID,
+			}
+			plan.Bulk.Orders = append(plan.Bulk.Orders, item)
+			plan.orders[refID] = state
+		}
+	}
+
+	for _, trade := range stale {
+		refID++
+		item := &BulkOrderItem{
+			TradeRequest: TradeRequest{
+				Type: trade.Type,
+				Pair: ladder.Pair,
+			},
+			ID:    trade.ID,
+			RefID: refID,
+		}
+		plan.Bulk.Cancel = append(plan.Bulk.Cancel, item)
+	}
+
+	return plan, nil
+}
+
+// Reconcile fetch the user's open orders on ladder's pair, send the minimal
+// bulk request to match the ladder, and return the state of each level.
+//
+// The cancellation is send before the new orders, in separate TradeBulk
+// request, since the server process the orders before the cancellation in
+// the same request.
+// Otherwise, if the balance is fully committed to the current open orders,
+// the new orders is rejected due to insufficient balance while the open
+// orders are cancelled, leaving no orders in the market.
+//
+// If one of the bulk request failed, it will return the partial result
+// along with the error.
+// If the cancellation request failed, the new orders are not send.
+func (lr *LadderReconciler) Reconcile(ladder *OrderLadder) (
+	result *LadderResult, err error,
+) {
+	logp := "Reconcile"
+
+	if ladder == nil {
+		return nil, nil
+	}
+
+	err = ladder.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+
+	pairTradesOpen, err := lr.account.UserOrdersOpen(ladder.Pair)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+
+	var open *TradesOpen
+	tradesOpen, ok := pairTradesOpen[ladder.Pair]
+	if ok {
+		open = &tradesOpen
+	}
+
+	plan, err := lr.Plan(ladder, open)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	if plan.IsEmpty() {
+		return plan.result, nil
+	}
+
+	bulks := []*TradeBulk{{
+		Pair:   plan.Bulk.Pair,
+		Cancel: plan.Bulk.Cancel,
+	}, {
+		Pair:   plan.Bulk.Pair,
+		Orders: plan.Bulk.Orders,
+	}}
+	for _, tbReq := range bulks {
+		if len(tbReq.Orders) == 0 && len(tbReq.Cancel) == 0 {
+			continue
+		}
+		tbRes, err := ExecuteTradeBulk(lr.trading, tbReq, lr.MaxBulkItems)
+		plan.Apply(tbRes)
+		if err != nil {
+			return plan.result, fmt.Errorf("%s: %w", logp, err)
+		}
+	}
+
+	return plan.result, nil
+}
+
+func newLadderLevelStates(tradeType string, levels []LadderLevel) (
+	states []LadderLevelState,
+) {
+	states = make([]LadderLevelState, 0, len(levels))
+	for _, level := range levels {
+		states = append(states, LadderLevelState{
+			LadderLevel: level,
+			Type:        tradeType,
+		})
+	}
+	return states
+}
+
+// matchLadderLevels mark each level state as kept if there is open order with
+// the same price and remaining amount.
+// It return list of open orders that does not match any level.
+func matchLadderLevels(states []LadderLevelState, open []Trade) (stale []Trade) {
+	used := make([]bool, len(open))
+
+	for x := range states {
+		state := &states[x]
+		for y := range open {
+			if used[y] {
+				continue
+			}
+			if !open[y].Price.IsEqual(state.Price) {
+				continue
+			}
+			if !tradeRemain(&open[y]).IsEqual(state.Amount) {
+				continue
+			}
+			used[y] = true
+			state.OrderID = open[y].ID
+			state.IsKept = true
+			break
+		}
+	}
+
+	for y := range open {
+		if !used[y] {
+			stale = append(stale, open[y])
+		}
+	}
+	return stale
+}
+
+// tradeRemain return the remaining coin amount of open order.
+func tradeRemain(trade *Trade) *big.Rat {
+	if trade.CoinRemain != nil {
+		return trade.CoinRemain
+	}
+	return trade.CoinAmount
+}
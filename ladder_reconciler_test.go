@@ -0,0 +1,341 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestLadderReconciler_Plan(t *testing.T) {
+	ladder := &OrderLadder{
+		Pair: PairBitcoinTether,
+		Asks: []LadderLevel{{
+			Price:  big.NewRat(101),
+			Amount: big.NewRat(1),
+		}, {
+			Price:  big.NewRat(102),
+			Amount: big.NewRat(2),
+		}},
+		Bids: []LadderLevel{{
+			Price:  big.NewRat(99),
+			Amount: big.NewRat(1),
+		}},
+	}
+	open := &TradesOpen{
+		Asks: []Trade{{
+			ID:         10,
+			Type:       TradeTypeAsk,
+			Price:      big.NewRat(101),
+			CoinRemain: big.NewRat(1),
+		}, {
+			ID:         11,
+			Type:       TradeTypeAsk,
+			Price:      big.NewRat(102),
+			CoinRemain: big.NewRat(1),
+		}},
+		Bids: []Trade{{
+			ID:         20,
+			Type:       TradeTypeBid,
+			Price:      big.NewRat(98),
+			CoinRemain: big.NewRat(1),
+		}},
+	}
+
+	lr := NewLadderReconciler(nil, nil)
+	plan, err := lr.Plan(ladder, open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expBulk := &TradeBulk{
+		Pair: PairBitcoinTether,
+		Orders: []*BulkOrderItem{{
+			TradeRequest: TradeRequest{
+				Price:  big.NewRat(102),
+				Amount: big.NewRat(2),
+				Type:   TradeTypeAsk,
+				Method: TradeMethodLimit,
+				Pair:   PairBitcoinTether,
+			},
+			RefID: 1,
+		}, {
+			TradeRequest: TradeRequest{
+				Price:  big.NewRat(99),
+				Amount: big.NewRat(1),
+				Type:   TradeTypeBid,
+				Method: TradeMethodLimit,
+				Pair:   PairBitcoinTether,
+			},
+			RefID: 2,
+		}},
+		Cancel: []*BulkOrderItem{{
+			TradeRequest: TradeRequest{
+				Type: TradeTypeAsk,
+				Pair: PairBitcoinTether,
+			},
+			ID:    11,
+			RefID: 3,
+		}, {
+			TradeRequest: TradeRequest{
+				Type: TradeTypeBid,
+				Pair: PairBitcoinTether,
+			},
+			ID:    20,
+			RefID: 4,
+		}},
+	}
+
+	test.Assert(t, "Plan.Bulk", expBulk, plan.Bulk)
+
+	tbRes := &TradeBulk{
+		Pair: PairBitcoinTether,
+		Orders: []*BulkOrderItem{{
+			E:     liberrors.E{Code: http.StatusOK},
+			ID:    30,
+			RefID: 1,
+		}, {
+			E: liberrors.E{
+				Code:    http.StatusBadRequest,
+				Message: "insufficient balance",
+				Name:    "ERR_INSUFFICIENT_BALANCE",
+			},
+			RefID: 2,
+		}},
+		Cancel: []*BulkOrderItem{{
+			E:     liberrors.E{Code: http.StatusOK},
+			ID:    11,
+			RefID: 3,
+		}, {
+			E: liberrors.E{
+				Code:    http.StatusNotFound,
+				Message: "order not found",
+			},
+			RefID: 4,
+		}},
+	}
+
+	trading := &tradingStub{
+		tradeBulk: func(tbReq *TradeBulk) (*TradeBulk, error) {
+			return tbRes, nil
+		},
+	}
+
+	bulkRes, err := ExecuteTradeBulk(trading, plan.Bulk, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := plan.Apply(bulkRes)
+
+	test.Assert(t, "Asks[0].OrderID", int64(10), result.Asks[0].OrderID)
+	test.Assert(t, "Asks[0].IsKept", true, result.Asks[0].IsKept)
+	test.Assert(t, "Asks[1].OrderID", int64(30), result.Asks[1].OrderID)
+	test.Assert(t, "Bids[0].IsLive", false, result.Bids[0].IsLive())
+	test.Assert(t, "Bids[0].Err.Name", "ERR_INSUFFICIENT_BALANCE",
+		result.Bids[0].Err.Name)
+	test.Assert(t, "len(Live)", 2, len(result.Live()))
+	test.Assert(t, "Cancelled", []*BulkOrderItem{expBulk.Cancel[0]},
+		result.Cancelled)
+	test.Assert(t, "len(CancelFailed)", 1, len(result.CancelFailed))
+	test.Assert(t, "CancelFailed[0].OrderID", int64(20),
+		result.CancelFailed[0].OrderID)
+}
+
+func TestLadderReconciler_Plan_noChanges(t *testing.T) {
+	ladder := &OrderLadder{
+		Pair: PairBitcoinTether,
+		Bids: []LadderLevel{{
+			Price:  big.NewRat(99),
+			Amount: big.NewRat(1),
+		}},
+	}
+	open := &TradesOpen{
+		Bids: []Trade{{
+			ID:         20,
+			Type:       TradeTypeBid,
+			Price:      big.NewRat(99),
+			CoinAmount: big.NewRat(1),
+		}},
+	}
+
+	lr := NewLadderReconciler(nil, nil)
+	plan, err := lr.Plan(ladder, open)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Assert(t, "IsEmpty", true, plan.IsEmpty())
+}
+
+type accountStub struct {
+	AccountAPI
+	open PairTradesOpen
+}
+
+func (stub *accountStub) UserOrdersOpen(pair string) (PairTradesOpen, error) {
+	return stub.open, nil
+}
+
+func TestLadderReconciler_Reconcile(t *testing.T) {
+	var (
+		ladder = &OrderLadder{
+			Pair: PairBitcoinTether,
+			Bids: []LadderLevel{{
+				Price:  big.NewRat(99),
+				Amount: big.NewRat(1),
+			}},
+		}
+		account = &accountStub{
+			open: PairTradesOpen{
+				PairBitcoinTether: TradesOpen{
+					Bids: []Trade{{
+						ID:         20,
+						Type:       TradeTypeBid,
+						Price:      big.NewRat(98),
+						CoinRemain: big.NewRat(1),
+					}},
+				},
+			},
+		}
+		errCancel = errors.New("connection refused")
+	)
+
+	cases := []struct {
+		cancelErr   error
+		desc        string
+		expRequests []string
+		expIsLive   bool
+		expIsErr    bool
+	}{{
+		desc:        "cancel before orders",
+		expRequests: []string{"cancel 20", "order 99"},
+		expIsLive:   true,
+	}, {
+		desc:        "cancel failed",
+		cancelErr:   errCancel,
+		expRequests: []string{"cancel 20"},
+		expIsErr:    true,
+	}}
+
+	for _, c := range cases {
+		var (
+			gotRequests []string
+
+			// isBalanceFree is true once the open order is
+			// cancelled, as the balance is fully committed.
+			isBalanceFree bool
+		)
+
+		trading := &tradingStub{
+			tradeBulk: func(tbReq *TradeBulk) (*TradeBulk, error) {
+				tbRes := &TradeBulk{}
+				for _, item := range tbReq.Orders {
+					gotRequests = append(gotRequests,
+						"order "+item.Price.String())
+					res := &BulkOrderItem{RefID: item.RefID}
+					if isBalanceFree {
+						res.Code = http.StatusOK
+						res.ID = 30
+					} else {
+						res.Code = http.StatusBadRequest
+						res.Name = "ERR_INSUFFICIENT_BALANCE"
+					}
+					tbRes.Orders = append(tbRes.Orders, res)
+				}
+				for _, item := range tbReq.Cancel {
+					gotRequests = append(gotRequests,
+						"cancel "+strconv.FormatInt(item.ID, 10))
+				}
+				if c.cancelErr != nil {
+					return nil, c.cancelErr
+				}
+				for _, item := range tbReq.Cancel {
+					isBalanceFree = true
+					tbRes.Cancel = append(tbRes.Cancel, &BulkOrderItem{
+						E:     liberrors.E{Code: http.StatusOK},
+						ID:    item.ID,
+						RefID: item.RefID,
+					})
+				}
+				return tbRes, nil
+			},
+		}
+
+		lr := NewLadderReconciler(account, trading)
+		result, err := lr.Reconcile(ladder)
+
+		test.Assert(t, c.desc+": requests", c.expRequests, gotRequests)
+		test.Assert(t, c.desc+": error", c.expIsErr, err != nil)
+		test.Assert(t, c.desc+": IsLive", c.expIsLive,
+			result.Bids[0].IsLive())
+	}
+}
+
+func TestLadderReconciler_Plan_invalid(t *testing.T) {
+	cases := []struct {
+		ladder *OrderLadder
+		expErr error
+		desc   string
+	}{{
+		desc:   "empty pair",
+		ladder: &OrderLadder{},
+		expErr: ErrInvalidPair,
+	}, {
+		desc: "nil price",
+		ladder: &OrderLadder{
+			Pair: PairBitcoinTether,
+			Asks: []LadderLevel{{Amount: big.NewRat(1)}},
+		},
+		expErr: ErrInvalidPrice,
+	}, {
+		desc: "zero price",
+		ladder: &OrderLadder{
+			Pair: PairBitcoinTether,
+			Bids: []LadderLevel{{
+				Price:  big.NewRat(0),
+				Amount: big.NewRat(1),
+			}},
+		},
+		expErr: ErrInvalidPrice,
+	}, {
+		desc: "nil amount",
+		ladder: &OrderLadder{
+			Pair: PairBitcoinTether,
+			Bids: []LadderLevel{{Price: big.NewRat(99)}},
+		},
+		expErr: ErrInvalidAmount,
+	}, {
+		desc: "negative amount",
+		ladder: &OrderLadder{
+			Pair: PairBitcoinTether,
+			Asks: []LadderLevel{{
+				Price:  big.NewRat(101),
+				Amount: big.NewRat(-1),
+			}},
+		},
+		expErr: ErrInvalidAmount,
+	}}
+
+	lr := NewLadderReconciler(nil, nil)
+
+	for _, c := range cases {
+		plan, err := lr.Plan(c.ladder, nil)
+		test.Assert(t, c.desc+": plan", (*LadderPlan)(nil), plan)
+		test.Assert(t, c.desc+": error", true, errors.Is(err, c.expErr))
+
+		// The Reconcile reject the ladder before fetching the open
+		// orders.
+		_, err = lr.Reconcile(c.ladder)
+		test.Assert(t, c.desc+": Reconcile", true,
+			errors.Is(err, c.expErr))
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// LadderResult contains the result of reconciling OrderLadder.
+type LadderResult struct {
+	// Asks contains the state of each ask level, in the same order as in
+	// OrderLadder.
+	Asks []LadderLevelState
+
+	// Bids contains the state of each bid level, in the same order as in
+	// OrderLadder.
+	Bids []LadderLevelState
+
+	// Cancelled contains list of open orders that has been cancelled.
+	Cancelled []*BulkOrderItem
+
+	// CancelFailed contains the result of open orders that failed to be
+	// cancelled.
+	CancelFailed []*TradeBulkItemResult
+}
+
+// Live return all levels that have open order in the market.
+func (res *LadderResult) Live() (live []LadderLevelState) {
+	for _, state := range res.Asks {
+		if state.IsLive() {
+			live = append(live, state)
+		}
+	}
+	for _, state := range res.Bids {
+		if state.IsLive() {
+			live = append(live, state)
+		}
+	}
+	return live
+}
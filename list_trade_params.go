@@ -4,6 +4,12 @@
 
 package camp
 
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
 // ListTradeParams represent parameters for querying user's trades, closed,
 // and open orders.
 type ListTradeParams struct {
@@ -33,3 +39,58 @@ type ListTradeParams struct {
 	// Then TimeBefore filter rows with trade's time less or equal than its value.
 	TimeBefore int64
 }
+
+// Pack the ListTradeParams object to be send by REST and/or WebSocket client.
+func (tp *ListTradeParams) Pack() (
+	params url.Values, wsparams *WebSocketParams, err error,
+) {
+	if len(tp.Sort) == 0 {
+		tp.Sort = SortDescending
+	} else {
+		tp.Sort = strings.ToLower(tp.Sort)
+	}
+	switch tp.Sort {
+	case SortAscending, SortDescending:
+	default:
+		return nil, nil, ErrInvalidSortBy
+	}
+	if tp.Limit <= 0 || tp.Limit > DefaultLimit {
+		tp.Limit = DefaultLimit
+	}
+
+	params = url.Values{
+		ParamNamePair:  []string{tp.Pair},
+		ParamNameSort:  []string{tp.Sort},
+		ParamNameLimit: []string{strconv.FormatInt(tp.Limit, 10)},
+	}
+	if tp.Offset > 0 {
+		params.Set(ParamNameOffset, strconv.FormatInt(tp.Offset, 10))
+	}
+	if tp.IDAfter > 0 {
+		params.Set(ParamNameIDAfter, strconv.FormatInt(tp.IDAfter, 10))
+	}
+	if tp.IDBefore > 0 {
+		params.Set(ParamNameIDBefore, strconv.FormatInt(tp.IDBefore, 10))
+	}
+	if tp.TimeAfter > 0 {
+		params.Set(ParamNameTimeAfter, strconv.FormatInt(tp.TimeAfter, 10))
+	}
+	if tp.TimeBefore > 0 {
+		params.Set(ParamNameTimeBefore, strconv.FormatInt(tp.TimeBefore, 10))
+	}
+
+	wsparams = &WebSocketParams{
+		TradeRequest: TradeRequest{
+			Pair: tp.Pair,
+		},
+		IDSortBy:   tp.Sort,
+		IDAfter:    tp.IDAfter,
+		IDBefore:   tp.IDBefore,
+		TimeAfter:  tp.TimeAfter,
+		TimeBefore: tp.TimeBefore,
+		Limit:      tp.Limit,
+		Offset:     tp.Offset,
+	}
+
+	return params, wsparams, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestListTradeParams_Pack(t *testing.T) {
+	cases := []struct {
+		desc        string
+		tp          ListTradeParams
+		expErr      error
+		expParams   url.Values
+		expWsparams *WebSocketParams
+	}{{
+		desc: "invalid sort",
+		tp: ListTradeParams{
+			Pair: PairBitcoinTether,
+			Sort: "random",
+		},
+		expErr: ErrInvalidSortBy,
+	}, {
+		desc: "default values",
+		tp: ListTradeParams{
+			Pair: PairBitcoinTether,
+		},
+		expParams: url.Values{
+			ParamNamePair:  []string{PairBitcoinTether},
+			ParamNameSort:  []string{SortDescending},
+			ParamNameLimit: []string{"100"},
+		},
+		expWsparams: &WebSocketParams{
+			TradeRequest: TradeRequest{
+				Pair: PairBitcoinTether,
+			},
+			IDSortBy: SortDescending,
+			Limit:    DefaultLimit,
+		},
+	}, {
+		desc: "with filters",
+		tp: ListTradeParams{
+			Pair:       PairBitcoinTether,
+			Sort:       "ASC",
+			Offset:     10,
+			Limit:      1000,
+			IDAfter:    1,
+			IDBefore:   2,
+			TimeAfter:  3,
+			TimeBefore: 4,
+		},
+		expParams: url.Values{
+			ParamNamePair:       []string{PairBitcoinTether},
+			ParamNameSort:       []string{SortAscending},
+			ParamNameLimit:      []string{"100"},
+			ParamNameOffset:     []string{"10"},
+			ParamNameIDAfter:    []string{"1"},
+			ParamNameIDBefore:   []string{"2"},
+			ParamNameTimeAfter:  []string{"3"},
+			ParamNameTimeBefore: []string{"4"},
+		},
+		expWsparams: &WebSocketParams{
+			TradeRequest: TradeRequest{
+				Pair: PairBitcoinTether,
+			},
+			IDSortBy:   SortAscending,
+			IDAfter:    1,
+			IDBefore:   2,
+			TimeAfter:  3,
+			TimeBefore: 4,
+			Limit:      DefaultLimit,
+			Offset:     10,
+		},
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		params, wsparams, err := c.tp.Pack()
+		if err != nil {
+			test.Assert(t, "error", c.expErr, err)
+			continue
+		}
+
+		test.Assert(t, "params", c.expParams, params)
+		test.Assert(t, "wsparams", c.expWsparams, wsparams)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// MarketDataAPI define the public market APIs that can be accessed without
+// credential.
+//
+// This interface is implemented by Client, WebSocketPublic, and
+// FailoverClient.
+type MarketDataAPI interface {
+	MarketDepths(pair string) (*MarketDepths, error)
+	MarketInfo() ([]MarketInfo, error)
+	MarketPrices() (MarketPrices, error)
+	MarketSummaries() (*MarketSummaries, error)
+	MarketTicker(pair string) (*MarketTicker, error)
+	MarketTrades(pair string, offset, limit int64) (*MarketTrades, error)
+	MarketTradesOpen(pair string) (*TradesOpen, error)
+}
@@ -0,0 +1,293 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// MarketDataHub distribute the public market data from single
+// WebSocketPublic connection to many subscribers.
+//
+// Each subscriber register the topic and pairs that they want to receive
+// in HubFilter, and the hub subscribe the union of all filters to server
+// using SubscriptionManager.
+//
+// The hub consume the NotifDepths, NotifTicker, NotifTrades, and
+// NotifSummaries channels in WebSocketPublic, so the application should
+// not read those channels or manage the subscription directly once the
+// hub is created.
+type MarketDataHub struct {
+	ws *WebSocketPublic
+	sm *SubscriptionManager
+
+	subscribers map[int64]*HubSubscription
+
+	// chClosed is closed when the hub is closed.
+	chClosed chan struct{}
+
+	lastID int64
+
+	// syncLocker serialize the sync, so the desired subscription is
+	// applied in order.
+	syncLocker sync.Mutex
+
+	locker   sync.Mutex
+	isClosed atomic.Bool
+}
+
+// NewMarketDataHub create new hub on top of WebSocketPublic.
+func NewMarketDataHub(ws *WebSocketPublic) (hub *MarketDataHub) {
+	hub = &MarketDataHub{
+		ws:          ws,
+		sm:          NewSubscriptionManager(ws),
+		subscribers: make(map[int64]*HubSubscription),
+		chClosed:    make(chan struct{}),
+	}
+	go hub.run()
+	return hub
+}
+
+// Close stop the hub, close all of its subscriptions, and unsubscribe all
+// topics from server.
+// The WebSocketPublic is not closed.
+func (hub *MarketDataHub) Close() (err error) {
+	if hub.isClosed.Swap(true) {
+		return nil
+	}
+	close(hub.chClosed)
+
+	hub.locker.Lock()
+	subscribers := hub.subscribers
+	hub.subscribers = make(map[int64]*HubSubscription)
+	hub.locker.Unlock()
+
+	for _, sub := range subscribers {
+		sub.stop()
+	}
+
+	_, err = hub.sm.Set(&PublicSubscription{})
+	return err
+}
+
+// Subscribe register new subscriber that receive the messages that match
+// with the filter from the channel C in HubSubscription.
+//
+// The qopts define the policy and capacity of subscriber queue.
+// If its nil, the queue use QueuePolicyDropOldest with capacity
+// DefaultQueueCapacity.
+// Using QueuePolicyBlock on slow subscriber will block the other
+// subscribers.
+func (hub *MarketDataHub) Subscribe(filter HubFilter, qopts *QueueOptions) (
+	sub *HubSubscription, err error,
+) {
+	if hub.isClosed.Load() {
+		return nil, errClosed
+	}
+
+	err = filter.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	sub = hub.add(filter, qopts)
+
+	err = hub.sync()
+	if err != nil {
+		sub.stop()
+		hub.locker.Lock()
+		delete(hub.subscribers, sub.id)
+		hub.locker.Unlock()
+		// Store the desired subscription without the removed
+		// subscriber, so it will not be restored on reconnect.
+		_ = hub.sync()
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// SubscribeFunc register new subscriber that call the handle function on
+// each message that match with the filter.
+// The handle function is called in the subscriber goroutine, so its does
+// not block other subscribers.
+func (hub *MarketDataHub) SubscribeFunc(
+	filter HubFilter, qopts *QueueOptions, handle func(msg *HubMessage),
+) (
+	sub *HubSubscription, err error,
+) {
+	sub, err = hub.Subscribe(filter, qopts)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			select {
+			case msg := <-sub.C:
+				handle(msg)
+			case <-sub.chClosed:
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// add new subscriber without updating the server subscription.
+func (hub *MarketDataHub) add(filter HubFilter, qopts *QueueOptions) (
+	sub *HubSubscription,
+) {
+	if qopts == nil {
+		qopts = &QueueOptions{
+			Policy: QueuePolicyDropOldest,
+		}
+	}
+
+	filter.Pairs = append([]string(nil), filter.Pairs...)
+
+	sub = &HubSubscription{
+		hub:      hub,
+		chClosed: make(chan struct{}),
+		Filter:   filter,
+	}
+	sub.queue = newTopicQueue(*qopts, (*HubMessage).key,
+		(*HubMessage).coalesce, sub.chClosed)
+	sub.C = sub.queue.out
+
+	hub.locker.Lock()
+	hub.lastID++
+	sub.id = hub.lastID
+	hub.subscribers[sub.id] = sub
+	hub.locker.Unlock()
+
+	return sub
+}
+
+// desired return the union of all subscriber filters.
+func (hub *MarketDataHub) desired() (subs *PublicSubscription) {
+	var (
+		depths = make(map[string]struct{})
+		ticker = make(map[string]struct{})
+		trades = make(map[string]struct{})
+	)
+
+	subs = &PublicSubscription{}
+
+	hub.locker.Lock()
+	for _, sub := range hub.subscribers {
+		switch sub.Filter.Topic {
+		case TopicDepths:
+			setAdd(depths, sub.Filter.Pairs)
+		case TopicTicker:
+			setAdd(ticker, sub.Filter.Pairs)
+		case TopicTrades:
+			setAdd(trades, sub.Filter.Pairs)
+		case TopicSummaries:
+			subs.Summaries = true
+		}
+	}
+	hub.locker.Unlock()
+
+	subs.Depths = setSlice(depths)
+	subs.Ticker = setSlice(ticker)
+	subs.Trades = setSlice(trades)
+	return subs
+}
+
+// dispatch push the message to all subscribers that match with the
+// message.
+func (hub *MarketDataHub) dispatch(msg *HubMessage) {
+	var matched []*HubSubscription
+
+	hub.locker.Lock()
+	for _, sub := range hub.subscribers {
+		if sub.Filter.isMatch(msg) {
+			matched = append(matched, sub)
+		}
+	}
+	hub.locker.Unlock()
+
+	for _, sub := range matched {
+		sub.queue.push(msg)
+	}
+}
+
+// remove the subscriber and update the server subscription.
+func (hub *MarketDataHub) remove(sub *HubSubscription) error {
+	hub.locker.Lock()
+	delete(hub.subscribers, sub.id)
+	hub.locker.Unlock()
+
+	if hub.isClosed.Load() {
+		return nil
+	}
+	return hub.sync()
+}
+
+// run consume the notification channels in WebSocketPublic and dispatch
+// it to subscribers until the hub or the WebSocketPublic closed.
+func (hub *MarketDataHub) run() {
+	var msg *HubMessage
+	for {
+		select {
+		case <-hub.chClosed:
+			return
+		case <-hub.ws.chClosed:
+			return
+		case depths := <-hub.ws.NotifDepths:
+			msg = &HubMessage{
+				Topic:  TopicDepths,
+				Pair:   depths.Pair,
+				Depths: &depths,
+			}
+		case tick := <-hub.ws.NotifTicker:
+			msg = &HubMessage{
+				Topic:  TopicTicker,
+				Pair:   tick.PairName,
+				Ticker: &tick,
+			}
+		case trade := <-hub.ws.NotifTrades:
+			msg = &HubMessage{
+				Topic: TopicTrades,
+				Pair:  trade.Pair,
+				Trade: &trade,
+			}
+		case summaries := <-hub.ws.NotifSummaries:
+			msg = &HubMessage{
+				Topic:     TopicSummaries,
+				Summaries: &summaries,
+			}
+		}
+		hub.dispatch(msg)
+	}
+}
+
+// sync apply the union of all subscriber filters to server.
+func (hub *MarketDataHub) sync() (err error) {
+	hub.syncLocker.Lock()
+	_, err = hub.sm.Set(hub.desired())
+	hub.syncLocker.Unlock()
+	return err
+}
+
+// setAdd add the list of values into set.
+func setAdd(set map[string]struct{}, values []string) {
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+}
+
+// setSlice return the sorted values in set.
+func setSlice(set map[string]struct{}) (values []string) {
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestMarketDataHub_dispatch(t *testing.T) {
+	hub := &MarketDataHub{
+		subscribers: make(map[int64]*HubSubscription),
+		chClosed:    make(chan struct{}),
+	}
+
+	subDepthsBTC := hub.add(HubFilter{
+		Topic: TopicDepths,
+		Pairs: []string{PairBitcoinTether},
+	}, nil)
+	subDepthsAll := hub.add(HubFilter{
+		Topic: TopicDepths,
+		Pairs: []string{PairBitcoinTether, PairEthereumTether},
+	}, nil)
+	subSummaries := hub.add(HubFilter{
+		Topic: TopicSummaries,
+	}, nil)
+	defer func() {
+		subDepthsBTC.stop()
+		subDepthsAll.stop()
+		subSummaries.stop()
+	}()
+
+	expDesired := &PublicSubscription{
+		Depths:    []string{PairBitcoinTether, PairEthereumTether},
+		Summaries: true,
+	}
+	test.Assert(t, "desired", expDesired, hub.desired())
+
+	msgETH := &HubMessage{
+		Topic:  TopicDepths,
+		Pair:   PairEthereumTether,
+		Depths: &MarketDepths{Pair: PairEthereumTether},
+	}
+	msgBTC := &HubMessage{
+		Topic:  TopicDepths,
+		Pair:   PairBitcoinTether,
+		Depths: &MarketDepths{Pair: PairBitcoinTether},
+	}
+	msgSummaries := &HubMessage{
+		Topic:     TopicSummaries,
+		Summaries: &MarketSummaries{},
+	}
+
+	hub.dispatch(msgETH)
+	hub.dispatch(msgBTC)
+	hub.dispatch(msgSummaries)
+
+	test.Assert(t, "subDepthsBTC", msgBTC, <-subDepthsBTC.C)
+	test.Assert(t, "subDepthsAll #1", msgETH, <-subDepthsAll.C)
+	test.Assert(t, "subDepthsAll #2", msgBTC, <-subDepthsAll.C)
+	test.Assert(t, "subSummaries", msgSummaries, <-subSummaries.C)
+}
+
+func TestHubFilter_validate(t *testing.T) {
+	cases := []struct {
+		expErr error
+		filter HubFilter
+	}{{
+		filter: HubFilter{Topic: "unknown"},
+		expErr: ErrInvalidTopic,
+	}, {
+		filter: HubFilter{Topic: TopicTrades},
+		expErr: ErrInvalidPair,
+	}, {
+		filter: HubFilter{Topic: TopicSummaries},
+	}, {
+		filter: HubFilter{
+			Topic: TopicTicker,
+			Pairs: []string{PairBitcoinTether},
+		},
+	}}
+
+	for _, c := range cases {
+		err := c.filter.validate()
+		test.Assert(t, c.filter.Topic, c.expErr, err)
+	}
+}
+
+func TestHubMessage_coalesce(t *testing.T) {
+	var (
+		prevDepths = &HubMessage{
+			Topic: TopicDepths,
+			Pair:  PairBitcoinTether,
+			Depths: &MarketDepths{
+				Pair: PairBitcoinTether,
+				Asks: []*Depth{newTestDepth(101, 1)},
+			},
+		}
+		nextDepths = &HubMessage{
+			Topic: TopicDepths,
+			Pair:  PairBitcoinTether,
+			Depths: &MarketDepths{
+				Pair: PairBitcoinTether,
+				Asks: []*Depth{newTestDepth(102, 1)},
+			},
+		}
+		nextTicker = &HubMessage{
+			Topic:  TopicTicker,
+			Pair:   PairBitcoinTether,
+			Ticker: &MarketTicker{PairName: PairBitcoinTether},
+		}
+	)
+
+	cases := []struct {
+		prev *HubMessage
+		next *HubMessage
+		exp  *HubMessage
+		desc string
+	}{{
+		desc: "depths",
+		prev: prevDepths,
+		next: nextDepths,
+		exp: &HubMessage{
+			Topic: TopicDepths,
+			Pair:  PairBitcoinTether,
+			Depths: &MarketDepths{
+				Pair: PairBitcoinTether,
+				Asks: []*Depth{
+					newTestDepth(101, 1),
+					newTestDepth(102, 1),
+				},
+				Bids: []*Depth{},
+			},
+		},
+	}, {
+		desc: "ticker",
+		prev: &HubMessage{
+			Topic:  TopicTicker,
+			Pair:   PairBitcoinTether,
+			Ticker: &MarketTicker{},
+		},
+		next: nextTicker,
+		exp:  nextTicker,
+	}}
+
+	for _, c := range cases {
+		test.Assert(t, c.desc, c.exp, c.prev.coalesce(c.next))
+	}
+
+	// The shared messages are not modified.
+	test.Assert(t, "prev depths", 1, len(prevDepths.Depths.Asks))
+	test.Assert(t, "next depths", 1, len(nextDepths.Depths.Asks))
+}
@@ -32,3 +32,46 @@ func (depths *MarketDepths) GetBidByPrice(price *big.Rat) (depth *Depth) {
 	}
 	return nil
 }
+
+// coalesceDepths combine the levels in prev and next, the depths from the
+// same pair, into new MarketDepths.
+// The level in next replace the level in prev with the same price, so the
+// result can be applied as single update in OrderBook, as if prev and next
+// applied in order.
+// The prev and next are not modified.
+func coalesceDepths(prev, next MarketDepths) MarketDepths {
+	return MarketDepths{
+		Pair: next.Pair,
+		Asks: coalesceLevels(prev.Asks, next.Asks),
+		Bids: coalesceLevels(prev.Bids, next.Bids),
+	}
+}
+
+// coalesceLevels return the union of levels in prev and next by price,
+// where the level in next replace the one in prev.
+func coalesceLevels(prev, next []*Depth) (levels []*Depth) {
+	var (
+		index = make(map[string]int, len(prev)+len(next))
+		key   string
+	)
+	levels = make([]*Depth, 0, len(prev)+len(next))
+	for _, list := range [][]*Depth{prev, next} {
+		for _, depth := range list {
+			// The invalid depth is kept as is, so its still
+			// reported by OrderBook.
+			if depth == nil || depth.Price == nil {
+				levels = append(levels, depth)
+				continue
+			}
+			key = depth.Price.String()
+			x, ok := index[key]
+			if ok {
+				levels[x] = depth
+				continue
+			}
+			index[key] = len(levels)
+			levels = append(levels, depth)
+		}
+	}
+	return levels
+}
@@ -0,0 +1,437 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+)
+
+// OrderBook contains the local copy of market depths on specific pair,
+// with the levels sorted by price.
+//
+// The OrderBook is seeded using Load, from the MarketDepths in
+// Client or WebSocketPublic, and then kept current by calling Update on
+// each MarketDepths received from NotifDepths.
+// Each depth in the update replace the level with the same price, and
+// the depth with zero TotalCoin remove the level.
+// The NotifDepths with QueuePolicyCoalesce can be used, since the
+// coalesced depths contains the levels from all combined updates.
+//
+// Each update is validated for integrity: the pair must be equal, the
+// depth must have positive price and non-negative amounts, and the book
+// must not crossed, where the best bid price is greater or equal to the
+// best ask price.
+// If the update is invalid, the update is discarded, the order book is
+// resynchronized from the snapshot using the MarketDataAPI in the last
+// Load, and the incident is reported to HandleIncident.
+// If HandleIncident is nil, the incident is logged instead.
+// Since the MarketDepths does not have sequence number, the missed update
+// cannot be detected until its cause one of the above errors.
+//
+// All methods are safe to be called concurrently.
+type OrderBook struct {
+	// api is the source of snapshot for resynchronization.
+	api MarketDataAPI
+
+	// HandleIncident define the callback that will be called when the
+	// update is invalid, after the order book resynchronized.
+	// If its nil, the incident is logged using the standard log.
+	// The callback must be set before calling Update.
+	HandleIncident OrderBookIncidentHandler
+
+	// asks sorted by price in ascending order, the best (lowest) ask
+	// is the first.
+	asks []*Depth
+
+	// bids sorted by price in descending order, the best (highest) bid
+	// is the first.
+	bids []*Depth
+
+	Pair string
+
+	locker sync.RWMutex
+}
+
+// NewOrderBook create new empty order book for the pair.
+func NewOrderBook(pair string) (ob *OrderBook) {
+	ob = &OrderBook{
+		Pair: pair,
+	}
+	return ob
+}
+
+// Asks return the copy of ask levels, sorted from the lowest price.
+func (ob *OrderBook) Asks() (asks []*Depth) {
+	ob.locker.RLock()
+	asks = copyDepths(ob.asks)
+	ob.locker.RUnlock()
+	return asks
+}
+
+// Bids return the copy of bid levels, sorted from the highest price.
+func (ob *OrderBook) Bids() (bids []*Depth) {
+	ob.locker.RLock()
+	bids = copyDepths(ob.bids)
+	ob.locker.RUnlock()
+	return bids
+}
+
+// AskByPrice return the copy of ask level at price, or nil if not exist.
+func (ob *OrderBook) AskByPrice(price *big.Rat) (depth *Depth) {
+	ob.locker.RLock()
+	x, ok := searchDepth(ob.asks, price, false)
+	if ok {
+		depth = copyDepth(ob.asks[x])
+	}
+	ob.locker.RUnlock()
+	return depth
+}
+
+// BidByPrice return the copy of bid level at price, or nil if not exist.
+func (ob *OrderBook) BidByPrice(price *big.Rat) (depth *Depth) {
+	ob.locker.RLock()
+	x, ok := searchDepth(ob.bids, price, true)
+	if ok {
+		depth = copyDepth(ob.bids[x])
+	}
+	ob.locker.RUnlock()
+	return depth
+}
+
+// BestAsk return the copy of ask level with the lowest price, or nil if
+// there is no asks.
+func (ob *OrderBook) BestAsk() (depth *Depth) {
+	ob.locker.RLock()
+	if len(ob.asks) > 0 {
+		depth = copyDepth(ob.asks[0])
+	}
+	ob.locker.RUnlock()
+	return depth
+}
+
+// BestBid return the copy of bid level with the highest price, or nil if
+// there is no bids.
+func (ob *OrderBook) BestBid() (depth *Depth) {
+	ob.locker.RLock()
+	if len(ob.bids) > 0 {
+		depth = copyDepth(ob.bids[0])
+	}
+	ob.locker.RUnlock()
+	return depth
+}
+
+// Mid return the average of best ask and best bid prices.
+// It return nil if one of the side is empty.
+func (ob *OrderBook) Mid() *big.Rat {
+	ob.locker.RLock()
+	defer ob.locker.RUnlock()
+
+	if len(ob.asks) == 0 || len(ob.bids) == 0 {
+		return nil
+	}
+	return big.AddRat(ob.asks[0].Price, ob.bids[0].Price).Quo(2)
+}
+
+// Spread return the difference between best ask and best bid prices.
+// It return nil if one of the side is empty.
+func (ob *OrderBook) Spread() *big.Rat {
+	ob.locker.RLock()
+	defer ob.locker.RUnlock()
+
+	if len(ob.asks) == 0 || len(ob.bids) == 0 {
+		return nil
+	}
+	return big.SubRat(ob.asks[0].Price, ob.bids[0].Price)
+}
+
+// Load replace the order book with the market depths fetched from api.
+// The api is used to resynchronize the order book when the update is
+// invalid.
+func (ob *OrderBook) Load(api MarketDataAPI) (err error) {
+	ob.locker.Lock()
+	ob.api = api
+	ob.locker.Unlock()
+
+	depths, err := api.MarketDepths(ob.Pair)
+	if err != nil {
+		return err
+	}
+	return ob.Reset(depths)
+}
+
+// PriceForVolume return the price level where the cumulative coin amount,
+// start from the best price, reach the volume.
+// The side is either TradeTypeAsk or TradeTypeBid.
+// It return nil if the total amount on the side is less than volume.
+func (ob *OrderBook) PriceForVolume(side string, volume *big.Rat) *big.Rat {
+	ob.locker.RLock()
+	defer ob.locker.RUnlock()
+
+	var total = big.NewRat(0)
+	for _, depth := range ob.side(side) {
+		total.Add(depth.TotalCoin)
+		if total.IsGreaterOrEqual(volume) {
+			return big.NewRat(depth.Price)
+		}
+	}
+	return nil
+}
+
+// Reset replace all levels in the order book with the depths.
+//
+// If the depths is from different pair or one of the depth is invalid,
+// the order book is not changed and it return ErrInvalidPair or
+// ErrOrderBookInvalidDepth.
+// If the depths is crossed, the order book still replaced and it return
+// ErrOrderBookCrossed.
+func (ob *OrderBook) Reset(depths *MarketDepths) (err error) {
+	if depths == nil {
+		return nil
+	}
+	if len(depths.Pair) != 0 && depths.Pair != ob.Pair {
+		return ErrInvalidPair
+	}
+
+	asks, bids, err := applyDepths(nil, nil, depths)
+	if errors.Is(err, ErrOrderBookInvalidDepth) {
+		return err
+	}
+
+	ob.locker.Lock()
+	ob.asks = asks
+	ob.bids = bids
+	ob.locker.Unlock()
+
+	return err
+}
+
+// Update apply the changes on depths into order book.
+// Each depth replace the level with the same price, and the depth with
+// zero TotalCoin remove the level.
+//
+// If the depths is from different pair, the depths is invalid, or the
+// order book become crossed, the update is discarded and the order book is
+// resynchronized from snapshot.
+// It return nil if the resynchronization success, otherwise it return one
+// of ErrInvalidPair, ErrOrderBookInvalidDepth, or ErrOrderBookCrossed.
+func (ob *OrderBook) Update(depths *MarketDepths) (err error) {
+	if depths == nil {
+		return nil
+	}
+	if depths.Pair != ob.Pair {
+		return ob.resync(depths, ErrInvalidPair)
+	}
+
+	var asks, bids []*Depth
+
+	ob.locker.Lock()
+	asks, bids, err = applyDepths(ob.asks, ob.bids, depths)
+	if err == nil {
+		ob.asks = asks
+		ob.bids = bids
+	}
+	ob.locker.Unlock()
+	if err == nil {
+		return nil
+	}
+
+	return ob.resync(depths, err)
+}
+
+// VolumeToPrice return the cumulative coin amount from the best price
+// until the price, inclusive.
+// The side is either TradeTypeAsk or TradeTypeBid.
+func (ob *OrderBook) VolumeToPrice(side string, price *big.Rat) (
+	volume *big.Rat,
+) {
+	ob.locker.RLock()
+	defer ob.locker.RUnlock()
+
+	var (
+		isBid = side == TradeTypeBid
+		x, ok = searchDepth(ob.side(side), price, isBid)
+	)
+	if ok {
+		x++
+	}
+
+	volume = big.NewRat(0)
+	for _, depth := range ob.side(side)[:x] {
+		volume.Add(depth.TotalCoin)
+	}
+	return volume
+}
+
+func (ob *OrderBook) report(inc *OrderBookIncident) {
+	if ob.HandleIncident != nil {
+		ob.HandleIncident(inc)
+		return
+	}
+	log.Printf("OrderBook %s: %s", inc.Pair, inc.Err)
+}
+
+// resync replace the order book with the snapshot from api and report
+// the incident.
+// It return nil if the resynchronization success.
+func (ob *OrderBook) resync(depths *MarketDepths, cause error) (err error) {
+	inc := &OrderBookIncident{
+		Time:   time.Now(),
+		Err:    cause,
+		Depths: depths,
+		Pair:   ob.Pair,
+	}
+
+	ob.locker.RLock()
+	api := ob.api
+	ob.locker.RUnlock()
+
+	if api == nil {
+		inc.ResyncErr = errors.New("resync: no snapshot source, call Load first")
+	} else {
+		inc.ResyncErr = ob.Load(api)
+	}
+	inc.IsResynced = inc.ResyncErr == nil
+
+	ob.report(inc)
+
+	if inc.IsResynced {
+		return nil
+	}
+	return cause
+}
+
+// side return the levels by trade type.
+// The caller must hold the lock.
+func (ob *OrderBook) side(side string) []*Depth {
+	if side == TradeTypeBid {
+		return ob.bids
+	}
+	return ob.asks
+}
+
+// applyDepths validate and apply the depths into the copy of asks and
+// bids levels, so the original levels are not modified.
+//
+// If one of the depth is invalid, it return the original levels and
+// ErrOrderBookInvalidDepth.
+// If the new levels is crossed, it return the new levels and
+// ErrOrderBookCrossed.
+func applyDepths(asks, bids []*Depth, depths *MarketDepths) (
+	newAsks, newBids []*Depth, err error,
+) {
+	for _, depth := range depths.Asks {
+		if !isValidDepth(depth) {
+			return asks, bids, ErrOrderBookInvalidDepth
+		}
+	}
+	for _, depth := range depths.Bids {
+		if !isValidDepth(depth) {
+			return asks, bids, ErrOrderBookInvalidDepth
+		}
+	}
+
+	newAsks = append([]*Depth(nil), asks...)
+	newBids = append([]*Depth(nil), bids...)
+
+	for _, depth := range depths.Asks {
+		newAsks = upsertDepth(newAsks, depth, false)
+	}
+	for _, depth := range depths.Bids {
+		newBids = upsertDepth(newBids, depth, true)
+	}
+
+	if len(newAsks) > 0 && len(newBids) > 0 &&
+		newBids[0].Price.IsGreaterOrEqual(newAsks[0].Price) {
+		return newAsks, newBids, ErrOrderBookCrossed
+	}
+	return newAsks, newBids, nil
+}
+
+// isValidDepth return true if the depth has positive price and
+// non-negative amounts.
+func isValidDepth(depth *Depth) bool {
+	if depth == nil || !depth.Price.IsGreaterThanZero() {
+		return false
+	}
+	return !depth.TotalCoin.IsLessThanZero() &&
+		!depth.TotalBase.IsLessThanZero() &&
+		!depth.Amount.IsLessThanZero()
+}
+
+func copyDepth(depth *Depth) *Depth {
+	return &Depth{
+		Amount:    copyRat(depth.Amount),
+		Price:     copyRat(depth.Price),
+		TotalBase: copyRat(depth.TotalBase),
+		TotalCoin: copyRat(depth.TotalCoin),
+	}
+}
+
+// copyRat return the copy of r, or nil if r is nil.
+func copyRat(r *big.Rat) *big.Rat {
+	if r == nil {
+		return nil
+	}
+	return big.NewRat(r)
+}
+
+func copyDepths(depths []*Depth) (list []*Depth) {
+	list = make([]*Depth, 0, len(depths))
+	for _, depth := range depths {
+		list = append(list, copyDepth(depth))
+	}
+	return list
+}
+
+// searchDepth find the index of price in the sorted levels using binary
+// search.
+// If the price is not found, it return the index where the price should
+// be inserted and false.
+func searchDepth(levels []*Depth, price *big.Rat, isDesc bool) (
+	x int, ok bool,
+) {
+	if price == nil {
+		return 0, false
+	}
+	x = sort.Search(len(levels), func(i int) bool {
+		cmp := levels[i].Price.Cmp(&price.Rat)
+		if isDesc {
+			return cmp <= 0
+		}
+		return cmp >= 0
+	})
+	ok = x < len(levels) && levels[x].Price.Cmp(&price.Rat) == 0
+	return x, ok
+}
+
+// upsertDepth insert, replace, or remove the level with the same price as
+// depth.
+func upsertDepth(levels []*Depth, depth *Depth, isDesc bool) []*Depth {
+	x, ok := searchDepth(levels, depth.Price, isDesc)
+
+	if !depth.TotalCoin.IsGreaterThanZero() {
+		if ok {
+			levels = append(levels[:x], levels[x+1:]...)
+		}
+		return levels
+	}
+
+	depth = copyDepth(depth)
+	if ok {
+		levels[x] = depth
+		return levels
+	}
+
+	levels = append(levels, nil)
+	copy(levels[x+1:], levels[x:])
+	levels[x] = depth
+	return levels
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "time"
+
+// OrderBookIncidentHandler define the callback when the OrderBook detect
+// an integrity error.
+type OrderBookIncidentHandler func(inc *OrderBookIncident)
+
+// OrderBookIncident contains the information about integrity error on
+// OrderBook and the result of resynchronization.
+type OrderBookIncident struct {
+	// Time when the incident detected.
+	Time time.Time
+
+	// Err is the cause of incident, one of ErrInvalidPair,
+	// ErrOrderBookCrossed, or ErrOrderBookInvalidDepth.
+	Err error
+
+	// ResyncErr is the error when resynchronizing the order book from
+	// snapshot, if any.
+	ResyncErr error
+
+	// Depths is the update that cause the incident.
+	Depths *MarketDepths
+
+	// Pair is the name of order book pair.
+	Pair string
+
+	// IsResynced is true if the order book has been replaced with the
+	// new snapshot successfully.
+	IsResynced bool
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+)
+
+func newTestDepth(price, coin int) *Depth {
+	return &Depth{
+		Price:     big.NewRat(price),
+		TotalCoin: big.NewRat(coin),
+	}
+}
+
+func TestOrderBook(t *testing.T) {
+	ob := NewOrderBook(PairBitcoinTether)
+
+	err := ob.Reset(&MarketDepths{
+		Pair: PairBitcoinTether,
+		Asks: []*Depth{
+			newTestDepth(103, 3),
+			newTestDepth(101, 1),
+			newTestDepth(102, 2),
+		},
+		Bids: []*Depth{
+			newTestDepth(98, 2),
+			newTestDepth(99, 1),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Assert(t, "Asks", []*Depth{
+		newTestDepth(101, 1),
+		newTestDepth(102, 2),
+		newTestDepth(103, 3),
+	}, ob.Asks())
+	test.Assert(t, "Bids", []*Depth{
+		newTestDepth(99, 1),
+		newTestDepth(98, 2),
+	}, ob.Bids())
+
+	test.Assert(t, "BestAsk", newTestDepth(101, 1), ob.BestAsk())
+	test.Assert(t, "BestBid", newTestDepth(99, 1), ob.BestBid())
+	test.Assert(t, "Spread", big.NewRat(2), ob.Spread())
+	test.Assert(t, "Mid", big.NewRat(100), ob.Mid())
+
+	test.Assert(t, "AskByPrice", newTestDepth(102, 2),
+		ob.AskByPrice(big.NewRat(102)))
+	test.Assert(t, "AskByPrice not found", (*Depth)(nil),
+		ob.AskByPrice(big.NewRat(100)))
+	test.Assert(t, "BidByPrice", newTestDepth(98, 2),
+		ob.BidByPrice(big.NewRat(98)))
+
+	test.Assert(t, "VolumeToPrice ask 102", big.NewRat(3),
+		ob.VolumeToPrice(TradeTypeAsk, big.NewRat(102)))
+	test.Assert(t, "VolumeToPrice ask 102.5", big.NewRat(3),
+		ob.VolumeToPrice(TradeTypeAsk, big.NewRat("102.5")))
+	test.Assert(t, "VolumeToPrice bid 98", big.NewRat(3),
+		ob.VolumeToPrice(TradeTypeBid, big.NewRat(98)))
+	test.Assert(t, "VolumeToPrice bid 100", big.NewRat(0),
+		ob.VolumeToPrice(TradeTypeBid, big.NewRat(100)))
+
+	test.Assert(t, "PriceForVolume ask 2", big.NewRat(102),
+		ob.PriceForVolume(TradeTypeAsk, big.NewRat(2)))
+	test.Assert(t, "PriceForVolume bid 5", (*big.Rat)(nil),
+		ob.PriceForVolume(TradeTypeBid, big.NewRat(5)))
+
+	err = ob.Update(&MarketDepths{
+		Pair: PairBitcoinTether,
+		Asks: []*Depth{
+			newTestDepth(101, 0),
+			newTestDepth(100, 4),
+		},
+		Bids: []*Depth{
+			newTestDepth(99, 5),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Assert(t, "Asks after update", []*Depth{
+		newTestDepth(100, 4),
+		newTestDepth(102, 2),
+		newTestDepth(103, 3),
+	}, ob.Asks())
+	test.Assert(t, "Bids after update", []*Depth{
+		newTestDepth(99, 5),
+		newTestDepth(98, 2),
+	}, ob.Bids())
+
+	err = ob.Update(&MarketDepths{Pair: PairEthereumTether})
+	test.Assert(t, "Update other pair", ErrInvalidPair, err)
+}
+
+type marketDataStub struct {
+	MarketDataAPI
+	marketDepths func(pair string) (*MarketDepths, error)
+}
+
+func (stub *marketDataStub) MarketDepths(pair string) (*MarketDepths, error) {
+	return stub.marketDepths(pair)
+}
+
+func TestOrderBook_Update_resync(t *testing.T) {
+	var (
+		snapshot = &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(101, 1)},
+			Bids: []*Depth{newTestDepth(99, 1)},
+		}
+		api = &marketDataStub{
+			marketDepths: func(pair string) (*MarketDepths, error) {
+				return snapshot, nil
+			},
+		}
+		incidents []*OrderBookIncident
+	)
+
+	ob := NewOrderBook(PairBitcoinTether)
+	ob.HandleIncident = func(inc *OrderBookIncident) {
+		incidents = append(incidents, inc)
+	}
+
+	err := ob.Load(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		depths *MarketDepths
+		expErr error
+		desc   string
+	}{{
+		desc: "crossed book",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Bids: []*Depth{newTestDepth(101, 1)},
+		},
+		expErr: ErrOrderBookCrossed,
+	}, {
+		desc: "negative amount",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(102, -1)},
+		},
+		expErr: ErrOrderBookInvalidDepth,
+	}, {
+		desc: "empty price",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{{TotalCoin: big.NewRat(1)}},
+		},
+		expErr: ErrOrderBookInvalidDepth,
+	}, {
+		desc: "different pair",
+		depths: &MarketDepths{
+			Pair: PairEthereumTether,
+			Asks: []*Depth{newTestDepth(2000, 1)},
+		},
+		expErr: ErrInvalidPair,
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		incidents = nil
+
+		err = ob.Update(c.depths)
+		test.Assert(t, "Update", nil, err)
+
+		test.Assert(t, "len(incidents)", 1, len(incidents))
+		test.Assert(t, "incident.Err", c.expErr, incidents[0].Err)
+		test.Assert(t, "incident.IsResynced", true,
+			incidents[0].IsResynced)
+		test.Assert(t, "Asks", snapshot.Asks, ob.Asks())
+		test.Assert(t, "Bids", snapshot.Bids, ob.Bids())
+	}
+
+	// Without snapshot source, the error is returned and the update is
+	// discarded.
+	ob = NewOrderBook(PairBitcoinTether)
+	err = ob.Reset(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases = []struct {
+		depths *MarketDepths
+		expErr error
+		desc   string
+	}{{
+		desc: "invalid depth without Load",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(102, -1)},
+		},
+		expErr: ErrOrderBookInvalidDepth,
+	}, {
+		desc: "crossed book without Load",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{
+				newTestDepth(101, 0),
+				newTestDepth(98, 1),
+			},
+		},
+		expErr: ErrOrderBookCrossed,
+	}, {
+		desc: "different pair without Load",
+		depths: &MarketDepths{
+			Pair: PairEthereumTether,
+		},
+		expErr: ErrInvalidPair,
+	}}
+
+	for _, c := range cases {
+		err = ob.Update(c.depths)
+		test.Assert(t, c.desc, c.expErr, err)
+		test.Assert(t, c.desc+": Asks", snapshot.Asks, ob.Asks())
+		test.Assert(t, c.desc+": Bids", snapshot.Bids, ob.Bids())
+	}
+}
+
+func TestOrderBook_Update_coalesced(t *testing.T) {
+	// Closing the channel stop the pump, so the depths are kept in the
+	// queue.
+	chClosed := make(chan struct{})
+	close(chClosed)
+
+	q := newTopicQueue(QueueOptions{Policy: QueuePolicyCoalesce},
+		func(depths MarketDepths) string {
+			return depths.Pair
+		}, coalesceDepths, chClosed)
+
+	q.push(MarketDepths{
+		Pair: PairBitcoinTether,
+		Asks: []*Depth{newTestDepth(102, 1)},
+		Bids: []*Depth{newTestDepth(98, 1)},
+	})
+	q.push(MarketDepths{
+		Pair: PairBitcoinTether,
+		Asks: []*Depth{newTestDepth(103, 2)},
+		Bids: []*Depth{newTestDepth(98, 0)},
+	})
+
+	test.Assert(t, "Coalesced", uint64(1), q.stats().Coalesced)
+
+	depths, ok := q.pop()
+	if !ok {
+		t.Fatal("pop: want coalesced depths, got empty queue")
+	}
+
+	ob := NewOrderBook(PairBitcoinTether)
+	err := ob.Reset(&MarketDepths{
+		Pair: PairBitcoinTether,
+		Asks: []*Depth{newTestDepth(101, 1)},
+		Bids: []*Depth{newTestDepth(98, 5), newTestDepth(97, 1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ob.Update(&depths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Assert(t, "Asks", []*Depth{
+		newTestDepth(101, 1),
+		newTestDepth(102, 1),
+		newTestDepth(103, 2),
+	}, ob.Asks())
+	test.Assert(t, "Bids", []*Depth{
+		newTestDepth(97, 1),
+	}, ob.Bids())
+}
+
+func TestOrderBook_Reset(t *testing.T) {
+	var (
+		snapshot = &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(101, 1)},
+			Bids: []*Depth{newTestDepth(99, 1)},
+		}
+		crossed = &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(100, 1)},
+			Bids: []*Depth{newTestDepth(100, 1)},
+		}
+	)
+
+	cases := []struct {
+		depths  *MarketDepths
+		expErr  error
+		expAsks []*Depth
+		expBids []*Depth
+		desc    string
+	}{{
+		desc: "invalid depth",
+		depths: &MarketDepths{
+			Pair: PairBitcoinTether,
+			Asks: []*Depth{newTestDepth(102, 1)},
+			Bids: []*Depth{newTestDepth(98, -1)},
+		},
+		expErr:  ErrOrderBookInvalidDepth,
+		expAsks: snapshot.Asks,
+		expBids: snapshot.Bids,
+	}, {
+		desc:    "different pair",
+		depths:  &MarketDepths{Pair: PairEthereumTether},
+		expErr:  ErrInvalidPair,
+		expAsks: snapshot.Asks,
+		expBids: snapshot.Bids,
+	}, {
+		desc:    "crossed",
+		depths:  crossed,
+		expErr:  ErrOrderBookCrossed,
+		expAsks: crossed.Asks,
+		expBids: crossed.Bids,
+	}}
+
+	for _, c := range cases {
+		ob := NewOrderBook(PairBitcoinTether)
+		err := ob.Reset(snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ob.Reset(c.depths)
+		test.Assert(t, c.desc+": error", c.expErr, err)
+		test.Assert(t, c.desc+": Asks", c.expAsks, ob.Asks())
+		test.Assert(t, c.desc+": Bids", c.expBids, ob.Bids())
+	}
+}
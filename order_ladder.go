@@ -0,0 +1,50 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "fmt"
+
+// OrderLadder contains the desired open limit orders on specific pair,
+// grouped by side.
+type OrderLadder struct {
+	// Pair name using "<coin>_<base>" format.
+	Pair string
+
+	// Asks contains the desired sell levels.
+	Asks []LadderLevel
+
+	// Bids contains the desired buy levels.
+	Bids []LadderLevel
+
+	// IsPostOnly set the post_only parameter on each new order.
+	IsPostOnly bool
+}
+
+// validate the ladder pair and the price and amount of each level.
+// The price and amount must be greater than zero.
+func (ladder *OrderLadder) validate() (err error) {
+	if len(ladder.Pair) == 0 {
+		return ErrInvalidPair
+	}
+	err = validateLadderLevels(TradeTypeAsk, ladder.Asks)
+	if err != nil {
+		return err
+	}
+	return validateLadderLevels(TradeTypeBid, ladder.Bids)
+}
+
+func validateLadderLevels(tradeType string, levels []LadderLevel) error {
+	for x, level := range levels {
+		if !level.Price.IsGreaterThanZero() {
+			return fmt.Errorf("%s level %d: %w", tradeType, x,
+				ErrInvalidPrice)
+		}
+		if !level.Amount.IsGreaterThanZero() {
+			return fmt.Errorf("%s level %d: %w", tradeType, x,
+				ErrInvalidAmount)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// PrivateEventHandler define the callback to receive the PrivateEvent.
+type PrivateEventHandler func(ev *PrivateEvent)
+
+// PrivateEvent contains the broadcast message from WebSocketPrivate.
+//
+// The same event is shared by all listeners, so it must not be modified.
+type PrivateEvent struct {
+	// Time when the event received.
+	Time time.Time
+
+	// Trade is set if the Type is PrivateEventOrderClosed,
+	// PrivateEventOrderTaken, or PrivateEventOrderCancelled.
+	Trade *Trade
+
+	// Message is the name of broadcast message from server, for example
+	// APIUserOrdersClosed.
+	Message string
+
+	// Body is the decoded body of message.
+	Body []byte
+
+	// Raw is the original frame payload from server.
+	Raw []byte
+
+	Type PrivateEventType
+}
+
+// newPrivateEvent create and decode the broadcast response into
+// PrivateEvent.
+// The message that cannot be decoded is returned as PrivateEventUnknown.
+func newPrivateEvent(res *websocket.Response, raw []byte) (ev *PrivateEvent) {
+	ev = &PrivateEvent{
+		Time:    time.Now(),
+		Message: res.Message,
+		Raw:     raw,
+	}
+
+	var err error
+
+	ev.Body, err = base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return ev
+	}
+
+	switch res.Message {
+	case APIUserOrdersClosed, WSMessageUserOrdersTaken:
+		trade := &Trade{}
+		err = json.Unmarshal(ev.Body, trade)
+		if err != nil {
+			return ev
+		}
+		ev.Trade = trade
+		switch {
+		case res.Message == WSMessageUserOrdersTaken:
+			ev.Type = PrivateEventOrderTaken
+		case trade.Status == TradeStatusCancelled:
+			ev.Type = PrivateEventOrderCancelled
+		default:
+			ev.Type = PrivateEventOrderClosed
+		}
+	}
+	return ev
+}
+
+// orderID return the order ID in the event, or zero if the event is not
+// related to order.
+func (ev *PrivateEvent) orderID() int64 {
+	if ev.Trade == nil {
+		return 0
+	}
+	return ev.Trade.ID
+}
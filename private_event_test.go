@@ -0,0 +1,200 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+func TestNewPrivateEvent(t *testing.T) {
+	cases := []struct {
+		expTrade *Trade
+		desc     string
+		message  string
+		body     string
+		expType  PrivateEventType
+	}{{
+		desc:    "order taken",
+		message: WSMessageUserOrdersTaken,
+		body:    `{"id":1,"pair":"btc_usdt","coin_filled":"0.5","coin_remain":"1.5"}`,
+		expType: PrivateEventOrderTaken,
+		expTrade: &Trade{
+			ID:         1,
+			Pair:       PairBitcoinTether,
+			CoinFilled: big.NewRat("0.5"),
+			CoinRemain: big.NewRat("1.5"),
+		},
+	}, {
+		desc:    "order closed",
+		message: APIUserOrdersClosed,
+		body:    `{"id":2,"status":"filled"}`,
+		expType: PrivateEventOrderClosed,
+		expTrade: &Trade{
+			ID:     2,
+			Status: TradeStatusFilled,
+		},
+	}, {
+		desc:    "order cancelled",
+		message: APIUserOrdersClosed,
+		body:    `{"id":3,"status":"cancelled"}`,
+		expType: PrivateEventOrderCancelled,
+		expTrade: &Trade{
+			ID:     3,
+			Status: TradeStatusCancelled,
+		},
+	}, {
+		desc:    "invalid body",
+		message: APIUserOrdersClosed,
+		body:    `{`,
+		expType: PrivateEventUnknown,
+	}, {
+		desc:    "unknown message",
+		message: "/v2/user/unknown",
+		body:    `{}`,
+		expType: PrivateEventUnknown,
+	}, {
+		desc:    "user info is not documented as broadcast",
+		message: APIUserInfo,
+		body:    `{"id":1}`,
+		expType: PrivateEventUnknown,
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		res := &websocket.Response{
+			Message: c.message,
+			Body:    base64.StdEncoding.EncodeToString([]byte(c.body)),
+		}
+
+		ev := newPrivateEvent(res, nil)
+
+		test.Assert(t, "Type", c.expType, ev.Type)
+		test.Assert(t, "Trade", c.expTrade, ev.Trade)
+		test.Assert(t, "Body", c.body, string(ev.Body))
+	}
+}
+
+func TestPrivateEvents_publish(t *testing.T) {
+	chClosed := make(chan struct{})
+	defer close(chClosed)
+
+	var (
+		pe = newPrivateEvents(2, QueueOptions{}, chClosed)
+		wg sync.WaitGroup
+		mu sync.Mutex
+
+		got = make(map[int64][]PrivateEventType)
+	)
+
+	handle := func(ev *PrivateEvent) {
+		mu.Lock()
+		got[ev.orderID()] = append(got[ev.orderID()], ev.Type)
+		mu.Unlock()
+		wg.Done()
+	}
+	id := pe.listen(handle)
+
+	events := []*PrivateEvent{{
+		Type:  PrivateEventOrderTaken,
+		Trade: &Trade{ID: 1},
+	}, {
+		Type:  PrivateEventOrderTaken,
+		Trade: &Trade{ID: 2},
+	}, {
+		Type:  PrivateEventOrderClosed,
+		Trade: &Trade{ID: 1},
+	}, {
+		Message: "/v2/user/unknown",
+	}}
+
+	wg.Add(len(events))
+	for _, ev := range events {
+		pe.publish(ev)
+	}
+	wg.Wait()
+
+	exp := map[int64][]PrivateEventType{
+		0: {PrivateEventUnknown},
+		1: {PrivateEventOrderTaken, PrivateEventOrderClosed},
+		2: {PrivateEventOrderTaken},
+	}
+	test.Assert(t, "events per order", exp, got)
+
+	// No listeners, the event is discarded.
+	pe.unlisten(id)
+	pe.publish(events[0])
+}
+
+func TestPrivateEvents_queuePolicy(t *testing.T) {
+	type testCase struct {
+		desc      string
+		policy    QueuePolicy
+		isBlocked bool
+	}
+
+	cases := []testCase{{
+		desc:      "block",
+		policy:    QueuePolicyBlock,
+		isBlocked: true,
+	}, {
+		desc:   "drop newest",
+		policy: QueuePolicyDropNewest,
+	}}
+
+	for _, c := range cases {
+		var (
+			chClosed = make(chan struct{})
+			qopts    = QueueOptions{
+				Policy:   c.policy,
+				Capacity: 1,
+			}
+			pe      = newPrivateEvents(1, qopts, chClosed)
+			started = make(chan struct{}, 10)
+			release = make(chan struct{})
+		)
+
+		// The listener block until released.
+		pe.listen(func(ev *PrivateEvent) {
+			started <- struct{}{}
+			<-release
+		})
+
+		pe.publish(&PrivateEvent{Trade: &Trade{ID: 1}})
+		<-started
+
+		published := make(chan struct{})
+		go func() {
+			for id := int64(2); id <= 5; id++ {
+				pe.publish(&PrivateEvent{Trade: &Trade{ID: id}})
+			}
+			close(published)
+		}()
+
+		var isBlocked bool
+		select {
+		case <-published:
+		case <-time.After(100 * time.Millisecond):
+			isBlocked = true
+		}
+		test.Assert(t, c.desc+": publish blocked", c.isBlocked, isBlocked)
+
+		close(release)
+		<-published
+
+		if !c.isBlocked {
+			qstats := pe.stats()
+			test.Assert(t, c.desc+": Dropped", true, qstats.Dropped > 0)
+		}
+		close(chClosed)
+	}
+}
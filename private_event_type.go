@@ -0,0 +1,49 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// PrivateEventType define the type of broadcast event in WebSocketPrivate.
+//
+// There is no event type for balance changes, because the server does not
+// broadcast them.
+// If the server send one, it is delivered as PrivateEventUnknown with the
+// raw message in PrivateEvent.Body.
+// Use WebSocketPrivate.UserInfo to fetch the latest balance.
+type PrivateEventType int
+
+// List of private event types.
+const (
+	// PrivateEventUnknown is the broadcast message that is not known by
+	// this library.
+	// The raw message is available in PrivateEvent.Body.
+	PrivateEventUnknown PrivateEventType = iota
+
+	// PrivateEventOrderClosed is the event when user's order is fully
+	// filled.
+	PrivateEventOrderClosed
+
+	// PrivateEventOrderTaken is the event when user's order is partially
+	// or fully filled.
+	PrivateEventOrderTaken
+
+	// PrivateEventOrderCancelled is the event when user's order is
+	// cancelled.
+	PrivateEventOrderCancelled
+)
+
+// String return the name of event type.
+func (evType PrivateEventType) String() string {
+	switch evType {
+	case PrivateEventUnknown:
+		return "unknown"
+	case PrivateEventOrderClosed:
+		return "order-closed"
+	case PrivateEventOrderTaken:
+		return "order-taken"
+	case PrivateEventOrderCancelled:
+		return "order-cancelled"
+	}
+	return "unknown"
+}
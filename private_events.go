@@ -0,0 +1,149 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"strconv"
+	"sync"
+)
+
+// privateEvents deliver the PrivateEvent to all registered listeners.
+//
+// The events are distributed into several workers by order ID, so the
+// events on the same order are delivered in order, while the events on
+// different orders can be delivered concurrently.
+// The events that is not related to order are delivered by the first
+// worker.
+//
+// Each worker has its own queue with the QueuePolicy from
+// WebSocketOptions.QueueEvents.
+// With QueuePolicyBlock, the publish block when the queue is full, until
+// the listeners consume the events.
+type privateEvents struct {
+	chClosed <-chan struct{}
+
+	listeners []privateListener
+	shards    []*topicQueue[*PrivateEvent]
+
+	lastID int64
+
+	locker sync.RWMutex
+}
+
+type privateListener struct {
+	handle PrivateEventHandler
+	id     int64
+}
+
+func newPrivateEvents(
+	nworker int, qopts QueueOptions, chClosed <-chan struct{},
+) (
+	pe *privateEvents,
+) {
+	if nworker <= 0 {
+		nworker = DefaultPrivateEventWorkers
+	}
+
+	pe = &privateEvents{
+		chClosed: chClosed,
+		shards:   make([]*topicQueue[*PrivateEvent], nworker),
+	}
+	for x := range pe.shards {
+		pe.shards[x] = newTopicQueue(qopts, privateEventKey, nil, chClosed)
+		go pe.worker(pe.shards[x].out)
+	}
+	return pe
+}
+
+// listen register new listener and return its ID.
+func (pe *privateEvents) listen(handle PrivateEventHandler) (id int64) {
+	pe.locker.Lock()
+	pe.lastID++
+	id = pe.lastID
+
+	// Copy on write, so the worker can iterate the listeners without
+	// holding the lock.
+	listeners := make([]privateListener, 0, len(pe.listeners)+1)
+	listeners = append(listeners, pe.listeners...)
+	pe.listeners = append(listeners, privateListener{
+		handle: handle,
+		id:     id,
+	})
+	pe.locker.Unlock()
+	return id
+}
+
+// publish the event to the worker based on its order ID.
+// The event is discarded if there is no listener.
+// If the worker queue is full, the event is handled based on the queue
+// policy.
+func (pe *privateEvents) publish(ev *PrivateEvent) {
+	pe.locker.RLock()
+	nlistener := len(pe.listeners)
+	pe.locker.RUnlock()
+	if nlistener == 0 {
+		return
+	}
+
+	orderID := ev.orderID()
+	if orderID < 0 {
+		orderID = -orderID
+	}
+	pe.shards[orderID%int64(len(pe.shards))].push(ev)
+}
+
+// stats return the sum of counters on all worker queues.
+func (pe *privateEvents) stats() (qstats QueueStats) {
+	for _, shard := range pe.shards {
+		shardStats := shard.stats()
+		qstats.Policy = shardStats.Policy
+		qstats.Length += shardStats.Length
+		qstats.Dropped += shardStats.Dropped
+		qstats.Coalesced += shardStats.Coalesced
+	}
+	return qstats
+}
+
+// unlisten remove the listener by its ID.
+func (pe *privateEvents) unlisten(id int64) {
+	pe.locker.Lock()
+	listeners := make([]privateListener, 0, len(pe.listeners))
+	for _, l := range pe.listeners {
+		if l.id != id {
+			listeners = append(listeners, l)
+		}
+	}
+	pe.listeners = listeners
+	pe.locker.Unlock()
+}
+
+// worker call all listeners on each event in the shard until the chClosed
+// is closed.
+func (pe *privateEvents) worker(shard <-chan *PrivateEvent) {
+	for {
+		select {
+		case <-pe.chClosed:
+			return
+		case ev := <-shard:
+			pe.locker.RLock()
+			listeners := pe.listeners
+			pe.locker.RUnlock()
+
+			for _, l := range listeners {
+				l.handle(ev)
+			}
+		}
+	}
+}
+
+// privateEventKey return the key of event for QueuePolicyCoalesce, the
+// order ID or the message name if the event is not related to order.
+func privateEventKey(ev *PrivateEvent) string {
+	id := ev.orderID()
+	if id == 0 {
+		return ev.Message
+	}
+	return strconv.FormatInt(id, 10)
+}
@@ -4,6 +4,14 @@
 
 package camp
 
+// List of topic names in PublicSubscription.
+const (
+	TopicDepths    = "depths"
+	TopicSummaries = "summaries"
+	TopicTicker    = "ticker"
+	TopicTrades    = "trades"
+)
+
 // PublicSubscription contains list of pairs that currently subscribed for
 // each topic: "depths", "ticker", and "trades".
 type PublicSubscription struct {
@@ -12,3 +20,19 @@ type PublicSubscription struct {
 	Trades    []string `json:"trades"`
 	Summaries bool     `json:"summaries"`
 }
+
+// clone return the deep copy of subscription.
+func (subs *PublicSubscription) clone() *PublicSubscription {
+	return &PublicSubscription{
+		Depths:    append([]string(nil), subs.Depths...),
+		Ticker:    append([]string(nil), subs.Ticker...),
+		Trades:    append([]string(nil), subs.Trades...),
+		Summaries: subs.Summaries,
+	}
+}
+
+// isEmpty return true if there is no subscription on all topics.
+func (subs *PublicSubscription) isEmpty() bool {
+	return len(subs.Depths) == 0 && len(subs.Ticker) == 0 &&
+		len(subs.Trades) == 0 && !subs.Summaries
+}
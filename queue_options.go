@@ -0,0 +1,27 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// DefaultQueueCapacity define the default number of messages in each
+// notification queue.
+const DefaultQueueCapacity = 256
+
+// QueueOptions define the options for notification channel of each topic
+// in WebSocketPublic.
+type QueueOptions struct {
+	// Policy define what to do when the queue is full.
+	// Default to QueuePolicyBlock.
+	Policy QueuePolicy
+
+	// Capacity define the maximum number of messages in the queue.
+	// Default to DefaultQueueCapacity.
+	Capacity int
+}
+
+func (qopts *QueueOptions) init() {
+	if qopts.Capacity <= 0 {
+		qopts.Capacity = DefaultQueueCapacity
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// QueuePolicy define how the WebSocket client deliver the broadcast
+// messages into notification channel when the consumer is slower than
+// the server.
+type QueuePolicy int
+
+// List of queue policies.
+const (
+	// QueuePolicyBlock wait until the consumer read the channel.
+	// Since the messages are read from single connection, slow consumer
+	// will block all other messages, including the response of
+	// requests.
+	// This is the default policy.
+	QueuePolicyBlock QueuePolicy = iota
+
+	// QueuePolicyDropOldest remove the oldest message in the queue when
+	// the queue is full.
+	QueuePolicyDropOldest
+
+	// QueuePolicyDropNewest discard the new message when the queue is
+	// full.
+	QueuePolicyDropNewest
+
+	// QueuePolicyCoalesce replace the message in the queue that has the
+	// same key with the new one, so the consumer only receive the latest
+	// one.
+	// The key for depths is the pair, for ticker is the pair, for trades
+	// is the order ID, and summaries only have one key.
+	//
+	// Since each depths is an update of levels, the queued depths is
+	// not replaced but combined with the new one by price, where the
+	// newer level replace the older one.
+	// The combined depths can be applied to OrderBook as if each depths
+	// is applied in order.
+	//
+	// If the queue is full, the oldest message is removed.
+	QueuePolicyCoalesce
+)
+
+// String return the name of policy.
+func (policy QueuePolicy) String() string {
+	switch policy {
+	case QueuePolicyBlock:
+		return "block"
+	case QueuePolicyDropOldest:
+		return "drop-oldest"
+	case QueuePolicyDropNewest:
+		return "drop-newest"
+	case QueuePolicyCoalesce:
+		return "coalesce"
+	}
+	return "unknown"
+}
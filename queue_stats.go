@@ -0,0 +1,22 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// QueueStats contains the counters of notification queue.
+type QueueStats struct {
+	// Policy of the queue.
+	Policy QueuePolicy
+
+	// Length is the number of messages currently in the queue.
+	Length int
+
+	// Dropped is the number of messages that has been removed or
+	// discarded because the queue is full.
+	Dropped uint64
+
+	// Coalesced is the number of messages that has been replaced by, or
+	// combined with, the newer one with the same key.
+	Coalesced uint64
+}
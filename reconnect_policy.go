@@ -0,0 +1,105 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// List of default values for ReconnectPolicy.
+const (
+	DefaultReconnectInitialDelay = 1 * time.Second
+	DefaultReconnectMaxDelay     = 30 * time.Second
+	DefaultReconnectMultiplier   = 2.0
+	DefaultReconnectJitter       = 0.2
+)
+
+// ReconnectPolicy define how the WebSocket client reconnect to server after
+// the connection is lost.
+//
+// The delay between attempts grow exponentially, starting from InitialDelay
+// and multiplied by Multiplier on each attempt, until its reach MaxDelay.
+// Each delay is randomized by Jitter to prevent many clients reconnecting
+// at the same time.
+type ReconnectPolicy struct {
+	// HandleGiveUp define the callback that will be called when the
+	// client stop reconnecting after MaxAttempts, with the last error
+	// from connect.
+	HandleGiveUp func(err error)
+
+	// InitialDelay define the delay before the first reconnect attempt.
+	// Default to DefaultReconnectInitialDelay.
+	InitialDelay time.Duration
+
+	// MaxDelay define the maximum delay between attempts.
+	// Default to DefaultReconnectMaxDelay.
+	MaxDelay time.Duration
+
+	// Multiplier define the factor to increase the delay on each
+	// attempt.
+	// Default to DefaultReconnectMultiplier.
+	Multiplier float64
+
+	// Jitter define the fraction of delay, between 0 and 1, that will be
+	// randomly added or subtracted from delay.
+	// For example, if the delay is 10 seconds and Jitter is 0.2, the
+	// actual delay is between 8 and 12 seconds.
+	// Default to DefaultReconnectJitter, set to negative value to
+	// disable it.
+	Jitter float64
+
+	// MaxAttempts define the maximum number of reconnect attempts before
+	// giving up.
+	// Zero means reconnect forever.
+	MaxAttempts int
+}
+
+// Delay return the duration to wait before reconnect attempt number n,
+// start from 1.
+func (policy *ReconnectPolicy) Delay(n int) (delay time.Duration) {
+	if n < 1 {
+		n = 1
+	}
+
+	fdelay := float64(policy.InitialDelay) *
+		math.Pow(policy.Multiplier, float64(n-1))
+	if fdelay > float64(policy.MaxDelay) {
+		fdelay = float64(policy.MaxDelay)
+	}
+	if policy.Jitter > 0 {
+		fdelay += fdelay * policy.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(fdelay)
+}
+
+// IsGiveUp return true if the reconnect attempt number n exceed the
+// MaxAttempts.
+func (policy *ReconnectPolicy) IsGiveUp(n int) bool {
+	return policy.MaxAttempts > 0 && n > policy.MaxAttempts
+}
+
+func (policy *ReconnectPolicy) init() {
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = DefaultReconnectInitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultReconnectMaxDelay
+	}
+	if policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = DefaultReconnectMultiplier
+	}
+	if policy.Jitter == 0 {
+		policy.Jitter = DefaultReconnectJitter
+	}
+	if policy.Jitter > 1 {
+		policy.Jitter = 1
+	}
+}
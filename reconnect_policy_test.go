@@ -0,0 +1,65 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestReconnectPolicy_Delay(t *testing.T) {
+	policy := &ReconnectPolicy{
+		InitialDelay: time.Second,
+		MaxDelay:     10 * time.Second,
+		Jitter:       -1,
+	}
+	policy.init()
+
+	cases := []struct {
+		n   int
+		exp time.Duration
+	}{{
+		n:   0,
+		exp: time.Second,
+	}, {
+		n:   1,
+		exp: time.Second,
+	}, {
+		n:   2,
+		exp: 2 * time.Second,
+	}, {
+		n:   4,
+		exp: 8 * time.Second,
+	}, {
+		n:   5,
+		exp: 10 * time.Second,
+	}, {
+		n:   100,
+		exp: 10 * time.Second,
+	}}
+
+	for _, c := range cases {
+		test.Assert(t, "Delay", c.exp, policy.Delay(c.n))
+	}
+
+	policy.Jitter = 0.5
+	for x := 0; x < 100; x++ {
+		got := policy.Delay(2)
+		if got < time.Second || got > 3*time.Second {
+			t.Fatalf("Delay with jitter: got %s, want between 1s and 3s", got)
+		}
+	}
+}
+
+func TestReconnectPolicy_IsGiveUp(t *testing.T) {
+	policy := &ReconnectPolicy{}
+	test.Assert(t, "unlimited", false, policy.IsGiveUp(1000))
+
+	policy.MaxAttempts = 3
+	test.Assert(t, "attempt 3", false, policy.IsGiveUp(3))
+	test.Assert(t, "attempt 4", true, policy.IsGiveUp(4))
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// List of SessionFrame directions.
+const (
+	SessionFrameIn  = "in"
+	SessionFrameOut = "out"
+)
+
+// SessionFrame contains single WebSocket text frame recorded by
+// SessionRecorder.
+type SessionFrame struct {
+	// Time when the frame received or send.
+	Time time.Time `json:"time"`
+
+	// Endpoint is the WebSocket path, either WSPublic or WSPrivate.
+	Endpoint string `json:"endpoint"`
+
+	// Direction is either SessionFrameIn, frame received from server, or
+	// SessionFrameOut, frame send to server.
+	Direction string `json:"direction"`
+
+	// Method and Target of request, for outbound frame.
+	Method string `json:"method,omitempty"`
+	Target string `json:"target,omitempty"`
+
+	// Message of response or broadcast, for inbound frame.
+	Message string `json:"message,omitempty"`
+
+	// Body is the decoded body of request or response.
+	Body string `json:"body,omitempty"`
+
+	// Raw contains the original payload of inbound frame that can not
+	// be recorded exactly in Body: the payload that is not valid JSON,
+	// the body that is not valid base64, or the decoded body that is not
+	// valid UTF-8.
+	// If its set, the frame is replayed using Raw as is.
+	Raw []byte `json:"raw,omitempty"`
+
+	// ID of request or response.
+	// Its zero for broadcast message.
+	ID uint64 `json:"id,omitempty"`
+
+	// Code is the status code of response, for inbound frame.
+	Code int32 `json:"code,omitempty"`
+}
+
+// payload return the inbound frame as the original payload from server.
+func (frame *SessionFrame) payload() ([]byte, error) {
+	if len(frame.Raw) != 0 {
+		return frame.Raw, nil
+	}
+	res := &websocket.Response{
+		ID:      frame.ID,
+		Code:    frame.Code,
+		Message: frame.Message,
+		Body:    base64.StdEncoding.EncodeToString([]byte(frame.Body)),
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// SessionRecorder write every inbound and outbound WebSocket frame as
+// SessionFrame in JSON Lines format.
+//
+// To record the session, set the WebSocketOptions.Recorder before
+// creating the client.
+// The same recorder can be shared by WebSocketPublic and WebSocketPrivate.
+type SessionRecorder struct {
+	w      io.Writer
+	enc    *json.Encoder
+	locker sync.Mutex
+}
+
+// NewSessionRecorder create new recorder that write the frames into w.
+func NewSessionRecorder(w io.Writer) (rec *SessionRecorder) {
+	rec = &SessionRecorder{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+	return rec
+}
+
+// CreateSessionRecorder create new file on path and record the frames
+// into it.
+// The file is created with mode 0600, since the session may contains the
+// user's private data; if the file exist it will be truncated.
+func CreateSessionRecorder(path string) (rec *SessionRecorder, err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("CreateSessionRecorder: %w", err)
+	}
+	return NewSessionRecorder(f), nil
+}
+
+// Close the underlying writer, if its implement io.Closer.
+func (rec *SessionRecorder) Close() error {
+	closer, ok := rec.w.(io.Closer)
+	if !ok {
+		return nil
+	}
+	rec.locker.Lock()
+	defer rec.locker.Unlock()
+	return closer.Close()
+}
+
+// Record write the frame.
+func (rec *SessionRecorder) Record(frame *SessionFrame) (err error) {
+	rec.locker.Lock()
+	err = rec.enc.Encode(frame)
+	rec.locker.Unlock()
+	return err
+}
+
+// recordIn record the payload received from server.
+func (rec *SessionRecorder) recordIn(endpoint string, payload []byte) {
+	frame := &SessionFrame{
+		Time:      time.Now(),
+		Endpoint:  endpoint,
+		Direction: SessionFrameIn,
+	}
+
+	res := &websocket.Response{}
+	err := json.Unmarshal(payload, res)
+	if err != nil {
+		// Keep the invalid payload as is.
+		frame.Raw = payload
+		rec.record(frame)
+		return
+	}
+
+	frame.ID = res.ID
+	frame.Code = res.Code
+	frame.Message = res.Message
+
+	body, err := base64.StdEncoding.DecodeString(res.Body)
+	switch {
+	case err != nil:
+		frame.Body = res.Body
+		frame.Raw = payload
+	case !utf8.Valid(body):
+		// The JSON encoder replace the invalid UTF-8 in string.
+		frame.Raw = payload
+	default:
+		frame.Body = string(body)
+	}
+
+	rec.record(frame)
+}
+
+// recordOut record the request send to server.
+func (rec *SessionRecorder) recordOut(
+	endpoint string, req *websocket.Request, body []byte,
+) {
+	rec.record(&SessionFrame{
+		Time:      time.Now(),
+		Endpoint:  endpoint,
+		Direction: SessionFrameOut,
+		Method:    req.Method,
+		Target:    req.Target,
+		Body:      string(body),
+		ID:        req.ID,
+	})
+}
+
+func (rec *SessionRecorder) record(frame *SessionFrame) {
+	err := rec.Record(frame)
+	if err != nil {
+		log.Printf("SessionRecorder: %s", err)
+	}
+}
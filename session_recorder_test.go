@@ -0,0 +1,113 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestCreateSessionRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	err := os.WriteFile(path, []byte("old content\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := CreateSessionRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rec.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "Size", int64(0), fi.Size())
+
+	// Remove the existing file to check the mode of the new file.
+	err = os.Remove(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err = CreateSessionRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rec.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err = os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "Mode", os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestSessionRecorder_recordIn(t *testing.T) {
+	type testCase struct {
+		desc    string
+		expBody string
+		payload []byte
+		expRaw  bool
+	}
+
+	cases := []testCase{{
+		desc:    "valid payload",
+		payload: newTestBroadcast(t, APIUserOrdersClosed, `{"id":7}`),
+		expBody: `{"id":7}`,
+	}, {
+		desc:    "invalid JSON",
+		payload: []byte(`{"message":`),
+		expRaw:  true,
+	}, {
+		desc:    "invalid base64 body",
+		payload: []byte(`{"message":"x","body":"not base64!"}`),
+		expBody: "not base64!",
+		expRaw:  true,
+	}, {
+		desc: "body is not valid UTF-8",
+		payload: []byte(`{"message":"x","body":"` +
+			base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe}) +
+			`"}`),
+		expRaw: true,
+	}}
+
+	for _, c := range cases {
+		var (
+			buf = &bytes.Buffer{}
+			rec = NewSessionRecorder(buf)
+		)
+
+		rec.recordIn(WSPrivate, c.payload)
+
+		rp, err := NewSessionReplayer(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame := rp.Frames[0]
+		test.Assert(t, c.desc+": Body", c.expBody, frame.Body)
+		test.Assert(t, c.desc+": has Raw", c.expRaw, len(frame.Raw) != 0)
+
+		got, err := frame.payload()
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Assert(t, c.desc+": payload", string(c.payload),
+			string(got))
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ReplayTarget is the WebSocket client that can receive the frames from
+// SessionReplayer, either WebSocketPublic or WebSocketPrivate.
+type ReplayTarget interface {
+	endpoint() string
+	handlePayload(payload []byte)
+}
+
+// SessionReplayer feed the inbound frames recorded by SessionRecorder
+// into WebSocketPublic or WebSocketPrivate.
+//
+// The target client can be created using NewWebSocketPublicReplay or
+// NewWebSocketPrivateReplay, so its does not connect to server.
+// Since the replayed response does not match with any requests, only the
+// broadcast messages are delivered, for example into NotifDepths or the
+// private event listeners.
+type SessionReplayer struct {
+	Frames []*SessionFrame
+
+	// Speed define the replay speed relative to the recorded time.
+	// For example, 1 replay at real speed and 10 replay ten times faster.
+	// Zero or negative value replay the frames without delay.
+	Speed float64
+}
+
+// NewSessionReplayer read the recorded frames from r.
+func NewSessionReplayer(r io.Reader) (rp *SessionReplayer, err error) {
+	var (
+		logp    = "NewSessionReplayer"
+		scanner = bufio.NewScanner(r)
+		n       int
+	)
+
+	// The frame may contains large body, for example MarketDepths.
+	scanner.Buffer(nil, 16*1024*1024)
+
+	rp = &SessionReplayer{}
+	for scanner.Scan() {
+		n++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		frame := &SessionFrame{}
+		err = json.Unmarshal(line, frame)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", logp, n, err)
+		}
+		rp.Frames = append(rp.Frames, frame)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", logp, err)
+	}
+	return rp, nil
+}
+
+// OpenSessionReplayer read the recorded frames from file.
+func OpenSessionReplayer(path string) (rp *SessionReplayer, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("OpenSessionReplayer: %w", err)
+	}
+	defer f.Close()
+
+	return NewSessionReplayer(f)
+}
+
+// Replay feed the inbound frames that has the same endpoint as target,
+// in order, until all frames replayed or the done channel closed.
+func (rp *SessionReplayer) Replay(target ReplayTarget, done <-chan struct{}) (
+	err error,
+) {
+	var (
+		endpoint = target.endpoint()
+		prev     time.Time
+	)
+	for _, frame := range rp.Frames {
+		if frame.Direction != SessionFrameIn || frame.Endpoint != endpoint {
+			continue
+		}
+
+		if rp.Speed > 0 && !prev.IsZero() {
+			delay := time.Duration(float64(frame.Time.Sub(prev)) / rp.Speed)
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-done:
+					timer.Stop()
+					return nil
+				case <-timer.C:
+				}
+			}
+		}
+		prev = frame.Time
+
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+
+		payload, err := frame.payload()
+		if err != nil {
+			return fmt.Errorf("Replay: %w", err)
+		}
+		target.handlePayload(payload)
+	}
+	return nil
+}
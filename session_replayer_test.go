@@ -0,0 +1,99 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/test"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+func newTestBroadcast(t *testing.T, message, body string) []byte {
+	res := &websocket.Response{
+		Message: message,
+		Body:    base64.StdEncoding.EncodeToString([]byte(body)),
+	}
+	payload, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestSessionReplayer_Replay(t *testing.T) {
+	var (
+		buf = &bytes.Buffer{}
+		rec = NewSessionRecorder(buf)
+	)
+
+	rec.recordOut(WSPublic, &websocket.Request{
+		ID:     1,
+		Method: "POST",
+		Target: APIMarketDepths,
+	}, []byte(`{"depths":["btc_usdt"]}`))
+	rec.recordIn(WSPublic, newTestBroadcast(t, APIMarketDepths,
+		`{"pair":"btc_usdt","asks":[{"price":"10","total_coin":"1"}]}`))
+	rec.recordIn(WSPrivate, newTestBroadcast(t, APIUserOrdersClosed,
+		`{"id":7,"status":"cancelled"}`))
+
+	rp, err := NewSessionReplayer(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, "len(Frames)", 3, len(rp.Frames))
+	test.Assert(t, "Frames[0].Direction", SessionFrameOut,
+		rp.Frames[0].Direction)
+	test.Assert(t, "Frames[0].Body", `{"depths":["btc_usdt"]}`,
+		rp.Frames[0].Body)
+
+	t.Run("public", func(t *testing.T) {
+		cl := NewWebSocketPublicReplay(nil)
+		defer cl.Close()
+
+		err := rp.Replay(cl, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case depths := <-cl.NotifDepths:
+			test.Assert(t, "Pair", PairBitcoinTether, depths.Pair)
+			test.Assert(t, "len(Asks)", 1, len(depths.Asks))
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for depths")
+		}
+	})
+
+	t.Run("private", func(t *testing.T) {
+		var (
+			cl   = NewWebSocketPrivateReplay(nil)
+			chev = make(chan *PrivateEvent, 1)
+		)
+		defer cl.Close()
+
+		remove := cl.Listen(func(ev *PrivateEvent) {
+			chev <- ev
+		})
+		defer remove()
+
+		err := rp.Replay(cl, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ev := <-chev:
+			test.Assert(t, "Type", PrivateEventOrderCancelled, ev.Type)
+			test.Assert(t, "Trade.ID", int64(7), ev.Trade.ID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+	})
+}
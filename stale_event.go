@@ -0,0 +1,37 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "time"
+
+// StaleHandler define the callback when the WebSocket connection or the
+// subscribed topic become stale or recovered.
+type StaleHandler func(ev *StaleEvent)
+
+// StaleEvent contains the information about stale connection or topic.
+type StaleEvent struct {
+	// Time when the event happened.
+	Time time.Time
+
+	// LastUpdate is the time when the last message received from
+	// server on the connection or topic.
+	LastUpdate time.Time
+
+	// Endpoint is the WebSocket path, either WSPublic or WSPrivate.
+	Endpoint string
+
+	// Topic is the name of subscription topic, for example TopicDepths.
+	// Its empty if the whole connection is stale, where the heartbeat
+	// request does not receive any response.
+	Topic string
+
+	// Pair is the name of pair in the topic.
+	// Its empty if the Topic is empty or TopicSummaries.
+	Pair string
+
+	// IsStale is true if the connection or topic become stale, and false
+	// when its receive update again.
+	IsStale bool
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"net/http"
+	"sync"
+)
+
+// SubscriptionManager manage the subscription of WebSocketPublic
+// declaratively.
+//
+// Instead of calling the Subscribe and Unsubscribe methods one by one, the
+// application declare the desired pairs on each topic, and the manager
+// compare it with the subscription reported by server, and then send the
+// minimal subscribe and unsubscribe requests.
+//
+// The desired subscription is kept in the manager, so when the
+// WebSocketPublic reconnected, the desired subscription is restored,
+// including the one that failed to be applied previously.
+type SubscriptionManager struct {
+	ws      *WebSocketPublic
+	desired *PublicSubscription
+	locker  sync.Mutex
+}
+
+// NewSubscriptionManager create new manager for the WebSocketPublic.
+// The current subscription in ws become the initial desired subscription.
+//
+// Only one manager should be created for each WebSocketPublic, the last
+// created manager will be used to restore the subscription after
+// reconnect.
+func NewSubscriptionManager(ws *WebSocketPublic) (sm *SubscriptionManager) {
+	sm = &SubscriptionManager{
+		ws:      ws,
+		desired: ws.subsCopy(),
+	}
+	ws.subsManager.Store(sm)
+	return sm
+}
+
+// Desired return the copy of desired subscription.
+func (sm *SubscriptionManager) Desired() (subs *PublicSubscription) {
+	sm.locker.Lock()
+	subs = sm.desired.clone()
+	sm.locker.Unlock()
+	return subs
+}
+
+// Set replace the desired subscription on all topics and apply it to
+// server.
+// On success it will return the latest subscription reported by server.
+func (sm *SubscriptionManager) Set(desired *PublicSubscription) (
+	*PublicSubscription, error,
+) {
+	if desired == nil {
+		desired = &PublicSubscription{}
+	}
+
+	sm.locker.Lock()
+	sm.desired = desired.clone()
+	sm.locker.Unlock()
+
+	return sm.Sync()
+}
+
+// SetSummaries set the desired subscription on topic "summaries" and
+// apply it to server.
+func (sm *SubscriptionManager) SetSummaries(isSubscribe bool) (
+	*PublicSubscription, error,
+) {
+	sm.locker.Lock()
+	sm.desired.Summaries = isSubscribe
+	sm.locker.Unlock()
+
+	return sm.Sync()
+}
+
+// SetTopic replace the desired pairs on topic TopicDepths, TopicTicker, or
+// TopicTrades, and apply it to server.
+// Empty pairNames means unsubscribe all pairs on the topic.
+func (sm *SubscriptionManager) SetTopic(topic string, pairNames []string) (
+	*PublicSubscription, error,
+) {
+	pairNames = append([]string(nil), pairNames...)
+
+	sm.locker.Lock()
+	switch topic {
+	case TopicDepths:
+		sm.desired.Depths = pairNames
+	case TopicTicker:
+		sm.desired.Ticker = pairNames
+	case TopicTrades:
+		sm.desired.Trades = pairNames
+	default:
+		sm.locker.Unlock()
+		return nil, ErrInvalidTopic
+	}
+	sm.locker.Unlock()
+
+	return sm.Sync()
+}
+
+// Sync fetch the current subscription from server and apply the
+// difference with desired subscription.
+// On success it will return the latest subscription reported by server.
+func (sm *SubscriptionManager) Sync() (*PublicSubscription, error) {
+	sm.locker.Lock()
+	defer sm.locker.Unlock()
+
+	_, err := sm.ws.Subscription()
+	if err != nil {
+		return nil, err
+	}
+
+	add, remove := diffSubscription(sm.desired, sm.ws.subsCopy())
+
+	if !remove.isEmpty() {
+		err = sm.ws.subscription(http.MethodDelete, remove)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !add.isEmpty() {
+		err = sm.ws.subscription(http.MethodPost, add)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sm.ws.subsCopy(), nil
+}
+
+// diffSubscription return the subscription that need to be added and
+// removed from current to become desired.
+func diffSubscription(desired, current *PublicSubscription) (
+	add, remove *PublicSubscription,
+) {
+	add = &PublicSubscription{
+		Depths:    diffPairs(desired.Depths, current.Depths),
+		Ticker:    diffPairs(desired.Ticker, current.Ticker),
+		Trades:    diffPairs(desired.Trades, current.Trades),
+		Summaries: desired.Summaries && !current.Summaries,
+	}
+	remove = &PublicSubscription{
+		Depths:    diffPairs(current.Depths, desired.Depths),
+		Ticker:    diffPairs(current.Ticker, desired.Ticker),
+		Trades:    diffPairs(current.Trades, desired.Trades),
+		Summaries: !desired.Summaries && current.Summaries,
+	}
+	return add, remove
+}
+
+// diffPairs return the unique pairs in a that is not exist in b.
+func diffPairs(a, b []string) (diff []string) {
+	exist := make(map[string]struct{}, len(b))
+	for _, pair := range b {
+		exist[pair] = struct{}{}
+	}
+	for _, pair := range a {
+		_, ok := exist[pair]
+		if ok {
+			continue
+		}
+		exist[pair] = struct{}{}
+		diff = append(diff, pair)
+	}
+	return diff
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestDiffSubscription(t *testing.T) {
+	cases := []struct {
+		desc      string
+		desired   *PublicSubscription
+		current   *PublicSubscription
+		expAdd    *PublicSubscription
+		expRemove *PublicSubscription
+	}{{
+		desc: "from empty",
+		desired: &PublicSubscription{
+			Depths:    []string{PairBitcoinTether, PairBitcoinTether},
+			Summaries: true,
+		},
+		current: &PublicSubscription{},
+		expAdd: &PublicSubscription{
+			Depths:    []string{PairBitcoinTether},
+			Summaries: true,
+		},
+		expRemove: &PublicSubscription{},
+	}, {
+		desc: "add and remove",
+		desired: &PublicSubscription{
+			Depths: []string{PairBitcoinTether},
+			Trades: []string{PairBitcoinTether},
+		},
+		current: &PublicSubscription{
+			Depths:    []string{PairBitcoinTether, PairEthereumTether},
+			Ticker:    []string{PairEthereumTether},
+			Summaries: true,
+		},
+		expAdd: &PublicSubscription{
+			Trades: []string{PairBitcoinTether},
+		},
+		expRemove: &PublicSubscription{
+			Depths:    []string{PairEthereumTether},
+			Ticker:    []string{PairEthereumTether},
+			Summaries: true,
+		},
+	}, {
+		desc: "no changes",
+		desired: &PublicSubscription{
+			Ticker: []string{PairBitcoinTether},
+		},
+		current: &PublicSubscription{
+			Ticker: []string{PairBitcoinTether},
+		},
+		expAdd:    &PublicSubscription{},
+		expRemove: &PublicSubscription{},
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		add, remove := diffSubscription(c.desired, c.current)
+
+		test.Assert(t, "add", c.expAdd, add)
+		test.Assert(t, "remove", c.expRemove, remove)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// topicQueue deliver the broadcast messages into the out channel based on
+// the QueuePolicy.
+//
+// For QueuePolicyBlock, the message is send directly into buffered out
+// channel.
+// For other policies, the message is stored in the queue and then
+// delivered into unbuffered out channel by pump goroutine, so the push
+// never block.
+type topicQueue[T any] struct {
+	out chan T
+
+	// notify the pump that new message has been pushed.
+	notify chan struct{}
+
+	// chClosed stop the pump and any blocking push.
+	chClosed <-chan struct{}
+
+	// key return the key of message for QueuePolicyCoalesce.
+	key func(msg T) string
+
+	// merge if its not nil, combine the queued message with the new
+	// message that has the same key for QueuePolicyCoalesce.
+	// If its nil, the queued message is replaced with the new one.
+	merge func(prev, next T) T
+
+	// entries and keys contains the pending messages, in order, and
+	// the index by key.
+	entries []*queueEntry[T]
+	keys    map[string]*queueEntry[T]
+
+	opts QueueOptions
+
+	dropped   atomic.Uint64
+	coalesced atomic.Uint64
+
+	locker sync.Mutex
+}
+
+type queueEntry[T any] struct {
+	msg T
+	key string
+}
+
+func newTopicQueue[T any](
+	opts QueueOptions, key func(T) string, merge func(prev, next T) T,
+	chClosed <-chan struct{},
+) (q *topicQueue[T]) {
+	opts.init()
+
+	q = &topicQueue[T]{
+		opts:     opts,
+		key:      key,
+		merge:    merge,
+		chClosed: chClosed,
+	}
+	if opts.Policy == QueuePolicyBlock {
+		q.out = make(chan T, opts.Capacity)
+		return q
+	}
+
+	q.out = make(chan T)
+	q.notify = make(chan struct{}, 1)
+	q.keys = make(map[string]*queueEntry[T])
+	go q.pump()
+	return q
+}
+
+// push the message into queue.
+func (q *topicQueue[T]) push(msg T) {
+	if q.opts.Policy == QueuePolicyBlock {
+		select {
+		case q.out <- msg:
+		case <-q.chClosed:
+		}
+		return
+	}
+
+	q.locker.Lock()
+	q.enqueue(msg)
+	q.locker.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue store the message based on the policy.
+// The caller must hold the lock.
+func (q *topicQueue[T]) enqueue(msg T) {
+	var entry *queueEntry[T]
+
+	if q.opts.Policy == QueuePolicyCoalesce {
+		k := q.key(msg)
+		entry = q.keys[k]
+		if entry != nil {
+			if q.merge != nil {
+				msg = q.merge(entry.msg, msg)
+			}
+			entry.msg = msg
+			q.coalesced.Add(1)
+			return
+		}
+		entry = &queueEntry[T]{msg: msg, key: k}
+	} else {
+		entry = &queueEntry[T]{msg: msg}
+	}
+
+	if len(q.entries) >= q.opts.Capacity {
+		if q.opts.Policy == QueuePolicyDropNewest {
+			q.dropped.Add(1)
+			return
+		}
+		q.shift()
+		q.dropped.Add(1)
+	}
+
+	q.entries = append(q.entries, entry)
+	if q.opts.Policy == QueuePolicyCoalesce {
+		q.keys[entry.key] = entry
+	}
+}
+
+// pop remove and return the oldest message in the queue.
+func (q *topicQueue[T]) pop() (msg T, ok bool) {
+	q.locker.Lock()
+	if len(q.entries) > 0 {
+		msg = q.shift().msg
+		ok = true
+	}
+	q.locker.Unlock()
+	return msg, ok
+}
+
+// pump deliver the messages in queue into out channel until the chClosed
+// is closed.
+func (q *topicQueue[T]) pump() {
+	for {
+		select {
+		case <-q.chClosed:
+			return
+		case <-q.notify:
+		}
+
+		for {
+			msg, ok := q.pop()
+			if !ok {
+				break
+			}
+			select {
+			case q.out <- msg:
+			case <-q.chClosed:
+				return
+			}
+		}
+	}
+}
+
+// shift remove the oldest entry.
+// The caller must hold the lock.
+func (q *topicQueue[T]) shift() (entry *queueEntry[T]) {
+	entry = q.entries[0]
+	q.entries[0] = nil
+	q.entries = q.entries[1:]
+	if q.keys != nil {
+		delete(q.keys, entry.key)
+	}
+	return entry
+}
+
+// stats return the current counters of the queue.
+func (q *topicQueue[T]) stats() (qstats QueueStats) {
+	qstats.Policy = q.opts.Policy
+	if q.opts.Policy == QueuePolicyBlock {
+		qstats.Length = len(q.out)
+	} else {
+		q.locker.Lock()
+		qstats.Length = len(q.entries)
+		q.locker.Unlock()
+	}
+	qstats.Dropped = q.dropped.Load()
+	qstats.Coalesced = q.coalesced.Load()
+	return qstats
+}
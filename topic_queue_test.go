@@ -0,0 +1,102 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestTopicQueue_push(t *testing.T) {
+	cases := []struct {
+		desc     string
+		msgs     []int
+		exp      []int
+		expStats QueueStats
+		policy   QueuePolicy
+	}{{
+		desc:   "drop oldest",
+		policy: QueuePolicyDropOldest,
+		msgs:   []int{1, 2, 3, 4},
+		exp:    []int{2, 3, 4},
+		expStats: QueueStats{
+			Policy:  QueuePolicyDropOldest,
+			Length:  3,
+			Dropped: 1,
+		},
+	}, {
+		desc:   "drop newest",
+		policy: QueuePolicyDropNewest,
+		msgs:   []int{1, 2, 3, 4},
+		exp:    []int{1, 2, 3},
+		expStats: QueueStats{
+			Policy:  QueuePolicyDropNewest,
+			Length:  3,
+			Dropped: 1,
+		},
+	}, {
+		desc:   "coalesce",
+		policy: QueuePolicyCoalesce,
+		// The key is the message modulo 10.
+		msgs: []int{1, 2, 11, 3, 12, 4},
+		exp:  []int{12, 3, 4},
+		expStats: QueueStats{
+			Policy:    QueuePolicyCoalesce,
+			Length:    3,
+			Dropped:   1,
+			Coalesced: 2,
+		},
+	}}
+
+	// Closing the channel stop the pump, so the messages are kept in
+	// the queue.
+	chClosed := make(chan struct{})
+	close(chClosed)
+
+	key := func(msg int) string {
+		return strconv.Itoa(msg % 10)
+	}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		opts := QueueOptions{
+			Policy:   c.policy,
+			Capacity: 3,
+		}
+		q := newTopicQueue(opts, key, nil, chClosed)
+		for _, msg := range c.msgs {
+			q.push(msg)
+		}
+
+		test.Assert(t, "stats", c.expStats, q.stats())
+
+		var got []int
+		for {
+			msg, ok := q.pop()
+			if !ok {
+				break
+			}
+			got = append(got, msg)
+		}
+		test.Assert(t, "messages", c.exp, got)
+	}
+}
+
+func TestTopicQueue_pump(t *testing.T) {
+	chClosed := make(chan struct{})
+	defer close(chClosed)
+
+	q := newTopicQueue(QueueOptions{Policy: QueuePolicyDropOldest},
+		strconv.Itoa, nil, chClosed)
+
+	q.push(1)
+	q.push(2)
+
+	test.Assert(t, "first", 1, <-q.out)
+	test.Assert(t, "second", 2, <-q.out)
+}
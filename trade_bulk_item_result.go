@@ -0,0 +1,34 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import liberrors "github.com/shuLhan/share/lib/errors"
+
+// TradeBulkItemResult contains the result of single order or cancellation
+// in TradeBulk request.
+type TradeBulkItemResult struct {
+	// Err contains the error for failed item, its nil if the item
+	// success.
+	Err *liberrors.E
+
+	// Request is the item from request.
+	Request *BulkOrderItem
+
+	// Response is the item from server response.
+	// Its nil if the item is not processed by server.
+	Response *BulkOrderItem
+
+	// OrderID is the ID of order that has been placed or cancelled.
+	OrderID int64
+
+	// IsCancel is true if the item is cancellation.
+	IsCancel bool
+}
+
+// IsSuccess return true if the item has been processed successfully by
+// server.
+func (item *TradeBulkItemResult) IsSuccess() bool {
+	return item.Err == nil
+}
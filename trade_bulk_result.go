@@ -0,0 +1,306 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+)
+
+// TradeBulkResult contains the result of each order and cancellation in
+// TradeBulk request, mapped back to their request item.
+type TradeBulkResult struct {
+	refs map[int64]*TradeBulkItemResult
+
+	// Orders contains the result of new orders, in the same order as
+	// request.
+	Orders []*TradeBulkItemResult
+
+	// Cancel contains the result of cancellation, in the same order as
+	// request.
+	Cancel []*TradeBulkItemResult
+
+	// Requests contains the number of TradeBulk requests send to server.
+	Requests int
+
+	OrdersSuccess int
+	OrdersFailed  int
+	CancelSuccess int
+	CancelFailed  int
+}
+
+// ExecuteTradeBulk send the TradeBulk request using api and return the
+// result of each item.
+//
+// If the number of orders and cancellation is greater than maxItems, the
+// request is splitted into several TradeBulk requests, orders first then
+// cancellation, and their results are merged.
+// If maxItems is less or equal to zero, it will set to
+// DefaultTradeBulkMaxItems.
+//
+// Each item with empty RefID will be set to unique RefID, so it can be
+// matched with the response.
+//
+// If one of the request failed, the items in that request are marked with
+// the error from server, ErrTradeBulkNotProcessed if the request is not
+// send, or ErrTradeBulkUnknown if the request may have been received by
+// server, for example on ErrWebSocketTimeout.
+// The item marked with ErrTradeBulkUnknown should not be retried before
+// checking its order status, to prevent the same order placed twice.
+// The rest of items are not send and marked with ErrTradeBulkNotProcessed,
+// and the partial result is returned along with the error.
+func ExecuteTradeBulk(api TradingAPI, tbReq *TradeBulk, maxItems int) (
+	res *TradeBulkResult, err error,
+) {
+	logp := "ExecuteTradeBulk"
+
+	if tbReq == nil {
+		return nil, nil
+	}
+	if maxItems <= 0 {
+		maxItems = DefaultTradeBulkMaxItems
+	}
+
+	res = newTradeBulkResult(tbReq)
+
+	for _, chunk := range splitTradeBulk(tbReq, maxItems) {
+		if err != nil {
+			res.fail(chunk, ErrTradeBulkNotProcessed)
+			continue
+		}
+
+		var tbRes *TradeBulk
+
+		res.Requests++
+		tbRes, err = api.TradeBulk(chunk)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", logp, err)
+			res.fail(chunk, err)
+			continue
+		}
+		res.apply(chunk, tbRes)
+	}
+
+	return res, err
+}
+
+// Failed return list of items that failed.
+func (res *TradeBulkResult) Failed() (failed []*TradeBulkItemResult) {
+	for _, item := range res.Orders {
+		if !item.IsSuccess() {
+			failed = append(failed, item)
+		}
+	}
+	for _, item := range res.Cancel {
+		if !item.IsSuccess() {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Get the item result by RefID of request item.
+func (res *TradeBulkResult) Get(refID int64) *TradeBulkItemResult {
+	return res.refs[refID]
+}
+
+// IsSuccess return true if all items success.
+func (res *TradeBulkResult) IsSuccess() bool {
+	return res.OrdersFailed == 0 && res.CancelFailed == 0
+}
+
+func newTradeBulkResult(tbReq *TradeBulk) (res *TradeBulkResult) {
+	res = &TradeBulkResult{
+		refs: make(map[int64]*TradeBulkItemResult),
+	}
+
+	var maxRefID int64
+	for _, items := range [][]*BulkOrderItem{tbReq.Orders, tbReq.Cancel} {
+		for _, item := range items {
+			if item.RefID > maxRefID {
+				maxRefID = item.RefID
+			}
+		}
+	}
+
+	for _, item := range tbReq.Orders {
+		if item.RefID <= 0 {
+			maxRefID++
+			item.RefID = maxRefID
+		}
+		itemRes := &TradeBulkItemResult{
+			Request: item,
+		}
+		res.Orders = append(res.Orders, itemRes)
+		res.refs[item.RefID] = itemRes
+	}
+	for _, item := range tbReq.Cancel {
+		if item.RefID <= 0 {
+			maxRefID++
+			item.RefID = maxRefID
+		}
+		itemRes := &TradeBulkItemResult{
+			Request:  item,
+			IsCancel: true,
+		}
+		res.Cancel = append(res.Cancel, itemRes)
+		res.refs[item.RefID] = itemRes
+	}
+	return res
+}
+
+// apply the response of chunk request into the result.
+func (res *TradeBulkResult) apply(chunk, tbRes *TradeBulk) {
+	if tbRes == nil {
+		tbRes = &TradeBulk{}
+	}
+
+	done := make(map[int64]bool, len(chunk.Orders)+len(chunk.Cancel))
+
+	for x, item := range tbRes.Orders {
+		refID := bulkItemRefID(item, chunk.Orders, x)
+		itemRes := res.refs[refID]
+		if itemRes == nil {
+			continue
+		}
+		itemRes.Response = item
+		if item.Code == http.StatusOK && item.ID > 0 {
+			itemRes.OrderID = item.ID
+			res.OrdersSuccess++
+		} else {
+			itemRes.Err = bulkItemError(item)
+			res.OrdersFailed++
+		}
+		done[refID] = true
+	}
+
+	for x, item := range tbRes.Cancel {
+		refID := bulkItemRefID(item, chunk.Cancel, x)
+		itemRes := res.refs[refID]
+		if itemRes == nil {
+			continue
+		}
+		itemRes.Response = item
+		itemRes.OrderID = itemRes.Request.ID
+		if item.Code == http.StatusOK {
+			res.CancelSuccess++
+		} else {
+			itemRes.Err = bulkItemError(item)
+			res.CancelFailed++
+		}
+		done[refID] = true
+	}
+
+	// Items that does not have response are considered failed.
+	for _, items := range [][]*BulkOrderItem{chunk.Orders, chunk.Cancel} {
+		for _, item := range items {
+			if done[item.RefID] {
+				continue
+			}
+			res.setFailed(res.refs[item.RefID], ErrTradeBulkNotProcessed)
+		}
+	}
+}
+
+// fail mark all items in chunk as failed because the request is failed.
+func (res *TradeBulkResult) fail(chunk *TradeBulk, err error) {
+	var (
+		errItem = ErrTradeBulkUnknown
+		errE    *liberrors.E
+	)
+
+	switch {
+	case isRequestNotSent(err):
+		errItem = ErrTradeBulkNotProcessed
+	case errors.Is(err, ErrWebSocketDisconnected),
+		errors.Is(err, ErrWebSocketTimeout):
+		// The connection lost or timeout after the request has been
+		// send.
+	case errors.As(err, &errE) && errE.Code != 0:
+		// The request rejected by server.
+		errItem = errE
+	}
+
+	for _, items := range [][]*BulkOrderItem{chunk.Orders, chunk.Cancel} {
+		for _, item := range items {
+			res.setFailed(res.refs[item.RefID], errItem)
+		}
+	}
+}
+
+func (res *TradeBulkResult) setFailed(itemRes *TradeBulkItemResult, err *liberrors.E) {
+	if itemRes == nil {
+		return
+	}
+	itemRes.Err = err
+	if itemRes.IsCancel {
+		res.CancelFailed++
+	} else {
+		res.OrdersFailed++
+	}
+}
+
+// isRequestNotSent return true if the err happened before the request send
+// to server, so the request is not processed by server.
+func isRequestNotSent(err error) bool {
+	if isFailover(err) {
+		return true
+	}
+	var errOp *net.OpError
+	return errors.As(err, &errOp) && errOp.Op == "dial"
+}
+
+// bulkItemError convert the failed item into error.
+func bulkItemError(item *BulkOrderItem) *liberrors.E {
+	return &liberrors.E{
+		Code:    item.Code,
+		Message: item.Message,
+		Name:    item.Name,
+	}
+}
+
+// bulkItemRefID return the RefID of response item, or the RefID of request
+// item in the same position if the response RefID is empty.
+func bulkItemRefID(item *BulkOrderItem, reqItems []*BulkOrderItem, x int) int64 {
+	if item.RefID > 0 {
+		return item.RefID
+	}
+	if x < len(reqItems) {
+		return reqItems[x].RefID
+	}
+	return 0
+}
+
+// splitTradeBulk split the orders and cancellation in tbReq into several
+// TradeBulk with maximum maxItems items.
+func splitTradeBulk(tbReq *TradeBulk, maxItems int) (chunks []*TradeBulk) {
+	var chunk *TradeBulk
+
+	next := func() {
+		if chunk == nil || len(chunk.Orders)+len(chunk.Cancel) >= maxItems {
+			chunk = &TradeBulk{
+				Pair: tbReq.Pair,
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	for _, item := range tbReq.Orders {
+		next()
+		chunk.Orders = append(chunk.Orders, item)
+	}
+	for _, item := range tbReq.Cancel {
+		next()
+		chunk.Cancel = append(chunk.Cancel, item)
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, &TradeBulk{Pair: tbReq.Pair})
+	}
+	return chunks
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// tradingStub implement TradingAPI for testing, only the TradeBulk method
+// is implemented.
+type tradingStub struct {
+	TradingAPI
+	tradeBulk func(tbReq *TradeBulk) (*TradeBulk, error)
+}
+
+func (stub *tradingStub) TradeBulk(tbReq *TradeBulk) (*TradeBulk, error) {
+	return stub.tradeBulk(tbReq)
+}
+
+func TestExecuteTradeBulk(t *testing.T) {
+	tbReq := &TradeBulk{
+		Pair: PairBitcoinTether,
+	}
+	for x := 0; x < 5; x++ {
+		tbReq.Orders = append(tbReq.Orders, &BulkOrderItem{
+			TradeRequest: TradeRequest{
+				Price:  big.NewRat(100 + x),
+				Amount: big.NewRat(1),
+				Type:   TradeTypeAsk,
+				Pair:   PairBitcoinTether,
+			},
+		})
+	}
+	tbReq.Cancel = []*BulkOrderItem{{
+		ID:    7,
+		RefID: 10,
+	}}
+
+	var (
+		orderID int64
+		chunks  []*TradeBulk
+	)
+
+	trading := &tradingStub{
+		tradeBulk: func(chunk *TradeBulk) (*TradeBulk, error) {
+			chunks = append(chunks, chunk)
+			if len(chunks) == 3 {
+				return nil, ErrInvalidPair
+			}
+
+			tbRes := &TradeBulk{}
+			for x, item := range chunk.Orders {
+				orderID++
+				resItem := &BulkOrderItem{
+					E:     liberrors.E{Code: http.StatusOK},
+					ID:    orderID,
+					RefID: item.RefID,
+				}
+				if x == 1 {
+					// Fail the second order on each request,
+					// with empty RefID.
+					resItem.E = liberrors.E{
+						Code: http.StatusBadRequest,
+						Name: "ERR_SELF_TRADE",
+					}
+					resItem.ID = 0
+					resItem.RefID = 0
+				}
+				tbRes.Orders = append(tbRes.Orders, resItem)
+			}
+			return tbRes, nil
+		},
+	}
+
+	res, err := ExecuteTradeBulk(trading, tbReq, 2)
+	test.Assert(t, "error", true, err != nil)
+
+	test.Assert(t, "len(chunks)", 3, len(chunks))
+	test.Assert(t, "Requests", 3, res.Requests)
+	test.Assert(t, "OrdersSuccess", 2, res.OrdersSuccess)
+	test.Assert(t, "OrdersFailed", 3, res.OrdersFailed)
+	test.Assert(t, "CancelFailed", 1, res.CancelFailed)
+	test.Assert(t, "IsSuccess", false, res.IsSuccess())
+	test.Assert(t, "len(Failed)", 4, len(res.Failed()))
+
+	test.Assert(t, "Orders[0].RefID", int64(11), res.Orders[0].Request.RefID)
+	test.Assert(t, "Orders[0].OrderID", int64(1), res.Orders[0].OrderID)
+	test.Assert(t, "Orders[1].Err.Name", "ERR_SELF_TRADE",
+		res.Orders[1].Err.Name)
+	test.Assert(t, "Orders[2].OrderID", int64(3), res.Get(13).OrderID)
+	test.Assert(t, "Orders[4].Err", ErrInvalidPair, res.Orders[4].Err)
+	test.Assert(t, "Cancel[0].Err", ErrInvalidPair, res.Get(10).Err)
+}
+
+func TestExecuteTradeBulk_fail(t *testing.T) {
+	errRejected := &liberrors.E{
+		Code:    http.StatusBadRequest,
+		Message: "rejected by server",
+	}
+
+	type testCase struct {
+		err  error
+		exp  *liberrors.E
+		desc string
+	}
+
+	cases := []testCase{{
+		desc: "connection closed before send",
+		err:  fmt.Errorf("TradeBulk: %w", websocket.ErrConnClosed),
+		exp:  ErrTradeBulkNotProcessed,
+	}, {
+		desc: "dial error",
+		err: &net.OpError{
+			Op:  "dial",
+			Err: errors.New("connection refused"),
+		},
+		exp: ErrTradeBulkNotProcessed,
+	}, {
+		desc: "WebSocket timeout",
+		err:  ErrWebSocketTimeout,
+		exp:  ErrTradeBulkUnknown,
+	}, {
+		desc: "WebSocket disconnected",
+		err:  ErrWebSocketDisconnected,
+		exp:  ErrTradeBulkUnknown,
+	}, {
+		desc: "read error after send",
+		err:  errors.New("read: connection reset by peer"),
+		exp:  ErrTradeBulkUnknown,
+	}, {
+		desc: "rejected by server",
+		err:  fmt.Errorf("TradeBulk: %w", errRejected),
+		exp:  errRejected,
+	}}
+
+	for _, c := range cases {
+		var (
+			tbReq = &TradeBulk{
+				Pair: PairBitcoinTether,
+				Orders: []*BulkOrderItem{{
+					TradeRequest: TradeRequest{
+						Price:  big.NewRat(100),
+						Amount: big.NewRat(1),
+						Type:   TradeTypeAsk,
+					},
+				}, {
+					TradeRequest: TradeRequest{
+						Price:  big.NewRat(101),
+						Amount: big.NewRat(1),
+						Type:   TradeTypeAsk,
+					},
+				}},
+			}
+			trading = &tradingStub{
+				tradeBulk: func(*TradeBulk) (*TradeBulk, error) {
+					return nil, c.err
+				},
+			}
+		)
+
+		res, err := ExecuteTradeBulk(trading, tbReq, 1)
+		test.Assert(t, c.desc+": error", true, err != nil)
+		test.Assert(t, c.desc+": Requests", 1, res.Requests)
+		test.Assert(t, c.desc+": Orders[0].Err", c.exp, res.Orders[0].Err)
+
+		// The next chunk is never send.
+		test.Assert(t, c.desc+": Orders[1].Err", ErrTradeBulkNotProcessed,
+			res.Orders[1].Err)
+	}
+}
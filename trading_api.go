@@ -0,0 +1,19 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// TradingAPI define the private APIs to place and cancel orders.
+//
+// This interface is implemented by Client, WebSocketPrivate, and
+// FailoverClient.
+type TradingAPI interface {
+	TradeAsk(treq *TradeRequest) (*TradeResponse, error)
+	TradeBid(treq *TradeRequest) (*TradeResponse, error)
+	TradeBulk(tbReq *TradeBulk) (*TradeBulk, error)
+	TradeCancel(trade *Trade) (*Trade, error)
+	TradeCancelAll() ([]Trade, error)
+	TradeCancelAsk(pair string, id int64) (*TradeResponse, error)
+	TradeCancelBid(pair string, id int64) (*TradeResponse, error)
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import "time"
+
+// WebSocketEventHandler define the callback when WebSocket client state
+// changes.
+type WebSocketEventHandler func(ev *WebSocketEvent)
+
+// WebSocketEvent contains the information of WebSocket client state
+// changes.
+type WebSocketEvent struct {
+	// Err contains the cause of state, for example the error from last
+	// reconnect attempt.
+	Err error
+
+	// Time when the event happened.
+	Time time.Time
+
+	// Endpoint is the WebSocket path, either WSPublic or WSPrivate.
+	Endpoint string
+
+	// State is the current state of the client.
+	State WebSocketState
+
+	// Attempt is the number of reconnect attempt, start from 1.
+	// Its zero if the state is not related to reconnecting.
+	Attempt int
+}
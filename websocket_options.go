@@ -0,0 +1,214 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultWebSocketTimeout define the default time to wait for
+	// response of each WebSocket request.
+	DefaultWebSocketTimeout = 30 * time.Second
+
+	// DefaultHeartbeatInterval define the default interval of heartbeat
+	// request on WebSocketPublic when no frame received from server.
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// DefaultPrivateEventWorkers define the default number of workers
+	// that deliver the PrivateEvent to listeners.
+	DefaultPrivateEventWorkers = 4
+)
+
+// errClosed define an internal error when the client is closed by user.
+var errClosed = errors.New("client is closed")
+
+// WebSocketOptions contains the optional configuration for WebSocketPublic
+// and WebSocketPrivate.
+type WebSocketOptions struct {
+	// Reconnect define the policy to reconnect to server after the
+	// connection lost.
+	// If its nil, the client will reconnect forever using default
+	// values in ReconnectPolicy.
+	Reconnect *ReconnectPolicy
+
+	// HandleEvent define the callback that will be called on each
+	// connection state changes.
+	// The callback is called synchronously, so it should not block.
+	HandleEvent WebSocketEventHandler
+
+	// HandleStale define the callback that will be called when the
+	// connection or subscribed topic become stale, and when the stale
+	// topic receive update again.
+	// The callback is called synchronously, so it should not block.
+	HandleStale StaleHandler
+
+	endpoint string
+
+	// Timeout define the maximum time to wait for response of each
+	// request.
+	// If the response is not received, the request return
+	// ErrWebSocketTimeout.
+	// Default to DefaultWebSocketTimeout, set to negative value to wait
+	// forever.
+	Timeout time.Duration
+
+	// HeartbeatInterval define the duration without receiving any frame
+	// from server before the client send heartbeat request.
+	// If the heartbeat request is timeout, the connection is considered
+	// stale (for example half-open TCP connection) and the client will
+	// reconnect.
+	//
+	// On WebSocketPublic, the heartbeat request get the current
+	// subscription, and its default to DefaultHeartbeatInterval.
+	// On WebSocketPrivate, the heartbeat request is the signed
+	// APIUserInfo that count to the rate limit, so its disabled by
+	// default; set it to positive value to enable it.
+	//
+	// Set to negative value to disable it.
+	HeartbeatInterval time.Duration
+
+	// StaleTimeout define the maximum duration without update on each
+	// subscribed pair on topic depths, ticker, and trades, and on topic
+	// summaries in WebSocketPublic.
+	// Once the duration passed, the pair is reported as stale to
+	// HandleStale.
+	// Default to zero, which disable the detection, since the pair with
+	// low activity may not receive any update for a long time.
+	StaleTimeout time.Duration
+
+	// IsReconnectOnStale if its true, the client will reconnect when one
+	// of the topic become stale.
+	// After reconnected, the subscriptions are restored and the consumer
+	// can resynchronize their states from NotifReconnected.
+	IsReconnectOnStale bool
+
+	// QueueDepths, QueueTicker, QueueTrades, and QueueSummaries define
+	// the policy and capacity of notification channel on each topic in
+	// WebSocketPublic, NotifDepths, NotifTicker, NotifTrades, and
+	// NotifSummaries.
+	QueueDepths    QueueOptions
+	QueueTicker    QueueOptions
+	QueueTrades    QueueOptions
+	QueueSummaries QueueOptions
+
+	// PrivateEventWorkers define the number of workers that deliver the
+	// PrivateEvent to listeners in WebSocketPrivate.
+	// Default to DefaultPrivateEventWorkers.
+	PrivateEventWorkers int
+
+	// QueueEvents define the policy and capacity of queue on each
+	// worker that deliver the PrivateEvent in WebSocketPrivate.
+	// With the default QueuePolicyBlock, the slow listener block the
+	// connection when the queue is full, including the responses of
+	// requests.
+	// For QueuePolicyCoalesce, the key of event is its order ID, so the
+	// listener may only receive the latest event of the same order.
+	QueueEvents QueueOptions
+
+	// Recorder if its set, record all the frames received from and
+	// requests sent to server, so the session can be replayed later
+	// using SessionReplayer.
+	Recorder *SessionRecorder
+}
+
+// init return the copy of options with default values and the endpoint
+// of client.
+// The options passed by caller is not modified, so the same options can be
+// shared by WebSocketPublic and WebSocketPrivate.
+func (opts *WebSocketOptions) init(endpoint string) (clientOpts *WebSocketOptions) {
+	clientOpts = &WebSocketOptions{}
+	if opts != nil {
+		*clientOpts = *opts
+	}
+
+	var reconnect ReconnectPolicy
+	if clientOpts.Reconnect != nil {
+		reconnect = *clientOpts.Reconnect
+	}
+	reconnect.init()
+	clientOpts.Reconnect = &reconnect
+
+	if clientOpts.Timeout == 0 {
+		clientOpts.Timeout = DefaultWebSocketTimeout
+	}
+	if clientOpts.HeartbeatInterval == 0 && endpoint != WSPrivate {
+		clientOpts.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	clientOpts.endpoint = endpoint
+	return clientOpts
+}
+
+// emit the state changes event to HandleEvent.
+func (opts *WebSocketOptions) emit(state WebSocketState, attempt int, err error) {
+	if opts.HandleEvent == nil {
+		return
+	}
+	ev := &WebSocketEvent{
+		Err:      err,
+		Time:     time.Now(),
+		Endpoint: opts.endpoint,
+		State:    state,
+		Attempt:  attempt,
+	}
+	opts.HandleEvent(ev)
+}
+
+// reconnect call the connect function until its success, the policy give
+// up, or the chClosed is closed.
+// If the chClosed is already closed, the connection is closed by user, so
+// it return errClosed without emitting any state.
+func (opts *WebSocketOptions) reconnect(
+	connect func() error, chClosed <-chan struct{},
+) (err error) {
+	logp := "reconnect"
+
+	select {
+	case <-chClosed:
+		return errClosed
+	default:
+	}
+
+	opts.emit(WebSocketStateDisconnected, 0, nil)
+
+	var attempt int
+	for {
+		attempt++
+		if opts.Reconnect.IsGiveUp(attempt) {
+			err = fmt.Errorf("%s: giving up after %d attempts: %w",
+				logp, attempt-1, err)
+			log.Printf("%s %s", opts.endpoint, err)
+			opts.emit(WebSocketStateClosed, attempt-1, err)
+			if opts.Reconnect.HandleGiveUp != nil {
+				opts.Reconnect.HandleGiveUp(err)
+			}
+			return err
+		}
+
+		delay := opts.Reconnect.Delay(attempt)
+		timer := time.NewTimer(delay)
+		select {
+		case <-chClosed:
+			timer.Stop()
+			return errClosed
+		case <-timer.C:
+		}
+
+		opts.emit(WebSocketStateReconnecting, attempt, err)
+
+		err = connect()
+		if err != nil {
+			log.Printf("%s %s: attempt %d: %s", logp, opts.endpoint,
+				attempt, err)
+			continue
+		}
+
+		opts.emit(WebSocketStateConnected, attempt, nil)
+		return nil
+	}
+}
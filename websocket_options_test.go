@@ -0,0 +1,89 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestWebSocketOptions_init(t *testing.T) {
+	opts := &WebSocketOptions{
+		Reconnect: &ReconnectPolicy{
+			MaxAttempts: 3,
+		},
+	}
+
+	var (
+		pubOpts  = opts.init(WSPublic)
+		privOpts = opts.init(WSPrivate)
+	)
+
+	test.Assert(t, "public endpoint", WSPublic, pubOpts.endpoint)
+	test.Assert(t, "private endpoint", WSPrivate, privOpts.endpoint)
+	test.Assert(t, "public Timeout", DefaultWebSocketTimeout, pubOpts.Timeout)
+	test.Assert(t, "public MaxAttempts", 3, pubOpts.Reconnect.MaxAttempts)
+	test.Assert(t, "public HeartbeatInterval", DefaultHeartbeatInterval,
+		pubOpts.HeartbeatInterval)
+	test.Assert(t, "private HeartbeatInterval", time.Duration(0),
+		privOpts.HeartbeatInterval)
+
+	// The options owned by caller is not modified.
+	test.Assert(t, "endpoint", "", opts.endpoint)
+	test.Assert(t, "Timeout", time.Duration(0), opts.Timeout)
+	test.Assert(t, "Reconnect.InitialDelay", time.Duration(0),
+		opts.Reconnect.InitialDelay)
+
+	var nilOpts *WebSocketOptions
+	test.Assert(t, "nil options endpoint", WSPublic,
+		nilOpts.init(WSPublic).endpoint)
+}
+
+func TestWebSocketOptions_reconnect(t *testing.T) {
+	type testCase struct {
+		expErr    error
+		desc      string
+		expStates []WebSocketState
+		isClosed  bool
+	}
+
+	cases := []testCase{{
+		desc: "connection lost",
+		expStates: []WebSocketState{
+			WebSocketStateDisconnected,
+			WebSocketStateReconnecting,
+			WebSocketStateConnected,
+		},
+	}, {
+		desc:     "closed by user",
+		isClosed: true,
+		expErr:   errClosed,
+	}}
+
+	for _, c := range cases {
+		var (
+			gotStates []WebSocketState
+			opts      = &WebSocketOptions{
+				Reconnect: &ReconnectPolicy{
+					InitialDelay: time.Millisecond,
+				},
+				HandleEvent: func(ev *WebSocketEvent) {
+					gotStates = append(gotStates, ev.State)
+				},
+			}
+			chClosed = make(chan struct{})
+		)
+		opts = opts.init(WSPublic)
+		if c.isClosed {
+			close(chClosed)
+		}
+
+		err := opts.reconnect(func() error { return nil }, chClosed)
+		test.Assert(t, c.desc+": error", c.expErr, err)
+		test.Assert(t, c.desc+": states", c.expStates, gotStates)
+	}
+}
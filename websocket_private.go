@@ -8,13 +8,11 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
-	"sync"
-	"time"
+	"sync/atomic"
 
 	"github.com/shuLhan/share/lib/websocket"
 )
@@ -23,25 +21,84 @@ import (
 // broadcast from server.
 type OrdersClosedHandler func(trade *Trade)
 
+// OrdersTakenHandler define a callback when receiving order taken
+// broadcast from server.
+type OrdersTakenHandler func(trade *Trade)
+
 // WebSocketPrivate define the private WebSocket client for APIv2.
 type WebSocketPrivate struct {
 	env  *Environment
 	conn *websocket.Client
+	opts *WebSocketOptions
 
-	requests map[uint64]chan *websocket.Response
+	// chClosed is closed when the client is closed by user or stop
+	// reconnecting.
+	chClosed chan struct{}
+
+	requests *wsRequests
+	watchdog *wsWatchdog
+	events   *privateEvents
 
 	// HandleOrdersClosed define the callback that will be called
 	// automatically by client when one of the user's orders closed in the
 	// market.
+	// The callback must be set before the client receive any messages
+	// and must not be changed later.
+	// Use Listen to register multiple callbacks at any time.
 	HandleOrdersClosed OrdersClosedHandler
 
-	requestsLocker sync.Mutex
+	// HandleOrdersTaken define the callback that will be called
+	// automatically by client when one of the user's open orders is
+	// partially or fully filled in the market.
+	// The trade contains the order with the latest filled and remaining
+	// amount.
+	// Same as HandleOrdersClosed, the callback must be set before the
+	// client receive any messages.
+	HandleOrdersTaken OrdersTakenHandler
+
+	isConnected atomic.Bool
+	isClosed    atomic.Bool
 }
 
 // NewWebSocketPrivate create and initialize new WebSocket connection to
 // private endpoint.
 func NewWebSocketPrivate(env *Environment) (
 	cl *WebSocketPrivate, err error,
+) {
+	return NewWebSocketPrivateWithOptions(env, nil)
+}
+
+// NewWebSocketPrivateWithOptions create and initialize new WebSocket
+// connection to private endpoint with custom options.
+// If opts is nil, it will use the default options.
+func NewWebSocketPrivateWithOptions(env *Environment, opts *WebSocketOptions) (
+	cl *WebSocketPrivate, err error,
+) {
+	cl = newWebSocketPrivate(env, opts)
+
+	err = cl.connectFirst()
+	if err != nil {
+		return nil, fmt.Errorf("NewWebSocketPrivate: %w", err)
+	}
+
+	go cl.watchdog.run(cl.heartbeat, cl.IsConnected, cl.conn.Quit,
+		cl.chClosed)
+
+	return cl, nil
+}
+
+// NewWebSocketPrivateReplay create new WebSocketPrivate that is not
+// connected to server, to receive the recorded frames from
+// SessionReplayer.
+// If opts is nil, it will use the default options.
+func NewWebSocketPrivateReplay(opts *WebSocketOptions) (cl *WebSocketPrivate) {
+	return newWebSocketPrivate(nil, opts)
+}
+
+// newWebSocketPrivate create and initialize the client without connecting
+// to server.
+func newWebSocketPrivate(env *Environment, opts *WebSocketOptions) (
+	cl *WebSocketPrivate,
 ) {
 	if env == nil {
 		env = NewEnvironment("", "")
@@ -49,42 +106,73 @@ func NewWebSocketPrivate(env *Environment) (
 	if len(env.Address) == 0 {
 		env.Address = DefaultAddress
 	}
+	opts = opts.init(WSPrivate)
 
 	cl = &WebSocketPrivate{
 		env: env,
 		conn: &websocket.Client{
 			Headers: make(http.Header),
 		},
-		requests: make(map[uint64](chan *websocket.Response)),
+		opts:     opts,
+		chClosed: make(chan struct{}),
+		requests: newWSRequests(),
+		watchdog: newWSWatchdog(opts),
 	}
+	cl.events = newPrivateEvents(opts.PrivateEventWorkers, opts.QueueEvents,
+		cl.chClosed)
 	if env.IsInsecure {
 		cl.conn.TLSConfig = &tls.Config{
 			InsecureSkipVerify: env.IsInsecure,
 		}
 	}
 
+	cl.requests.recorder = opts.Recorder
+	cl.requests.endpoint = cl.endpoint()
+
 	cl.conn.HandleText = cl.handleText
 	cl.conn.HandleQuit = cl.handleUnexpectedQuit
 
-	err = cl.connect()
-	if err != nil {
-		return nil, fmt.Errorf("NewWebSocketPrivate: %w", err)
-	}
-
-	return cl, nil
+	return cl
 }
 
 // Close the connection and release all the resource.
 func (cl *WebSocketPrivate) Close() error {
-	cl.requestsLocker.Lock()
-	for id, ch := range cl.requests {
-		ch <- nil
-		close(ch)
-		delete(cl.requests, id)
+	isClosing := cl.shutdown()
+
+	cl.requests.failAll()
+
+	cl.isConnected.Store(false)
+
+	err := cl.conn.Close()
+	if isClosing {
+		cl.opts.emit(WebSocketStateClosed, 0, nil)
 	}
-	cl.requestsLocker.Unlock()
+	return err
+}
 
-	return cl.conn.Close()
+// IsConnected return true if the client is currently connected to server.
+func (cl *WebSocketPrivate) IsConnected() bool {
+	return cl.isConnected.Load()
+}
+
+// Listen register the callback to receive the broadcast events from
+// server.
+// The events on the same order are delivered in order.
+// The events that cannot be decoded by this library are delivered as
+// PrivateEventUnknown with its raw message.
+//
+// The returned function remove the callback.
+func (cl *WebSocketPrivate) Listen(handle PrivateEventHandler) (remove func()) {
+	id := cl.events.listen(handle)
+	return func() {
+		cl.events.unlisten(id)
+	}
+}
+
+// QueueStats return the sum of counters of PrivateEvent queues on all
+// workers.
+func (cl *WebSocketPrivate) QueueStats() QueueStats {
+	return cl.events.stats()
 }
 
 // TradeAsk request to sell the coin on market with specific method, amount,
@@ -143,6 +231,41 @@ func (cl *WebSocketPrivate) TradeBid(treq *TradeRequest) (
 	return cl.sendTradeRequest(http.MethodPost, APITradeBid, wsparams)
 }
 
+// TradeBulk request trade with multiple orders and/or cancellation.
+func (cl *WebSocketPrivate) TradeBulk(tbReq *TradeBulk) (
+	tbRes *TradeBulk, err error,
+) {
+	if tbReq == nil {
+		return nil, nil
+	}
+
+	tbReq.Timestamp = timestamp()
+
+	body, err := json.Marshal(tbReq)
+	if err != nil {
+		return nil, fmt.Errorf("TradeBulk: %w", err)
+	}
+
+	res, err := cl.sendBody(http.MethodPost, APITradeBulk, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resb, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	tbRes = &TradeBulk{}
+
+	err = json.Unmarshal(resb, tbRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return tbRes, nil
+}
+
 // TradeCancel cancel the open trade using ID and pair information in Trade.
 func (cl *WebSocketPrivate) TradeCancel(trade *Trade) (
 	*Trade, error,
@@ -249,6 +372,42 @@ func (cl *WebSocketPrivate) UserInfo() (user *User, err error) {
 	return user, nil
 }
 
+// UserOrdersClosed fetch the user closed orders based on pair's name.
+// The timeAfter and timeBefore parameters define a filter of records by range
+// of submit time.
+// If timeAfter is zero, its default to current timestamp.
+// If timeBefore is zero, its default to timeAfter - 1 hour.
+func (cl *WebSocketPrivate) UserOrdersClosed(
+	pairName string, timeAfter, timeBefore int64,
+) (
+	trades []Trade, err error,
+) {
+	wsparams := &WebSocketParams{
+		TradeRequest: TradeRequest{
+			Pair: pairName,
+		},
+		TimeAfter:  timeAfter,
+		TimeBefore: timeBefore,
+	}
+
+	res, err := cl.send(http.MethodGet, APIUserOrdersClosed, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	resb, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(resb, &trades)
+	if err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
 // UserOrderInfo fetch a single user's trade information based on pair's name
 // and trade ID.
 func (cl *WebSocketPrivate) UserOrderInfo(pairName string, id int64) (
@@ -314,6 +473,120 @@ func (cl *WebSocketPrivate) UserOrdersOpen(pairName string) (
 	return pairTradesOpen, nil
 }
 
+// UserTrades list the user's trade history, ordered from latest to oldest
+// one.
+func (cl *WebSocketPrivate) UserTrades(tp ListTradeParams) (
+	trades []Trade, err error,
+) {
+	_, wsparams, err := tp.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cl.send(http.MethodGet, APIUserTrades, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	resb, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(resb, &trades)
+	if err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+// UserTransactions fetch all user deposit and withdraw transaction history.
+// If the asset name is not empty, it will fetch only the deposit and withdraw
+// based on the asset name.
+//
+// The limit parameter define the maximum record in result set.
+func (cl *WebSocketPrivate) UserTransactions(asset string, limit int64) (
+	trans *AssetTransactions, err error,
+) {
+	wsparams := &WebSocketParams{
+		Asset: asset,
+	}
+	if limit > 0 && limit <= DefaultLimit {
+		wsparams.Limit = limit
+	}
+
+	res, err := cl.send(http.MethodGet, APIUserTransactions, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	resb, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	trans = &AssetTransactions{}
+
+	err = json.Unmarshal(resb, trans)
+	if err != nil {
+		return nil, err
+	}
+
+	return trans, nil
+}
+
+// UserWithdraw withdraw your assets into another address.
+//
+// This method require the "withdraw" permission and Callback URL, see the
+// Client.UserWithdraw for more information.
+func (cl *WebSocketPrivate) UserWithdraw(wreq *WithdrawRequest) (
+	withdraw *WithdrawItem, err error,
+) {
+	if wreq == nil {
+		return nil, nil
+	}
+
+	_, wsparams, err := wreq.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cl.send(http.MethodPost, APIUserWithdraw, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	resb, err := base64.StdEncoding.DecodeString(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	withdraw = &WithdrawItem{}
+
+	err = json.Unmarshal(resb, withdraw)
+	if err != nil {
+		return nil, err
+	}
+
+	return withdraw, nil
+}
+
+// connectFirst connect to server for the first time.
+func (cl *WebSocketPrivate) connectFirst() (err error) {
+	cl.opts.emit(WebSocketStateConnecting, 0, nil)
+
+	err = cl.connect()
+	if err != nil {
+		cl.shutdown()
+		cl.opts.emit(WebSocketStateClosed, 0, err)
+		return err
+	}
+
+	cl.opts.emit(WebSocketStateConnected, 0, nil)
+	return nil
+}
+
 func (cl *WebSocketPrivate) connect() error {
 	params := make(url.Values)
 
@@ -332,6 +605,8 @@ func (cl *WebSocketPrivate) connect() error {
 		return fmt.Errorf("connect: %w", err)
 	}
 
+	cl.isConnected.Store(true)
+
 	return nil
 }
 
@@ -349,33 +624,14 @@ func (cl *WebSocketPrivate) send(
 		}
 	}
 
-	req := &websocket.Request{
-		ID:     uint64(time.Now().UnixNano()),
-		Method: method,
-		Target: target,
-		Body:   base64.StdEncoding.EncodeToString(body),
-	}
-
-	payload, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	chres := cl.requestPush(req)
-
-	err = cl.conn.SendText(payload)
-	if err != nil {
-		cl.requestPop(req.ID)
-		return nil, err
-	}
-
-	res = <-chres
-
-	if res.Code != http.StatusOK {
-		return nil, errors.New(res.Message)
-	}
+	return cl.sendBody(method, target, body)
+}
 
-	return res, nil
+// sendBody send the raw request body to server and wait for the response.
+func (cl *WebSocketPrivate) sendBody(method, target string, body []byte) (
+	res *websocket.Response, err error,
+) {
+	return cl.requests.do(cl.conn, cl.opts.Timeout, method, target, body)
 }
 
 func (cl *WebSocketPrivate) sendTradeRequest(
@@ -408,83 +664,103 @@ func (cl *WebSocketPrivate) handleText(
 ) (
 	err error,
 ) {
-	var (
-		res     = &websocket.Response{}
-		payload = frame.Payload()
-	)
+	payload := frame.Payload()
 
-	err = json.Unmarshal(payload, res)
+	if cl.opts.Recorder != nil {
+		cl.opts.Recorder.recordIn(cl.endpoint(), payload)
+	}
+
+	cl.handlePayload(payload)
+
+	return nil
+}
+
+// endpoint return the WebSocket endpoint of client, used by
+// SessionRecorder to label the frames and by SessionReplayer to filter
+// them.
+func (cl *WebSocketPrivate) endpoint() string {
+	return WSPrivate
+}
+
+// handlePayload handle the text payload received from server, either
+// as response of request or as broadcast.
+func (cl *WebSocketPrivate) handlePayload(payload []byte) {
+	res := &websocket.Response{}
+
+	cl.watchdog.touch()
+
+	err := json.Unmarshal(payload, res)
 	if err != nil {
 		log.Printf("handleText: %q: %s", payload, err.Error())
-		return nil
+		return
 	}
 
 	if res.ID != 0 {
-		chres := cl.requestPop(res.ID)
+		chres := cl.requests.pop(res.ID)
 		if chres != nil {
 			chres <- res
 		}
-		return nil
+		return
 	}
 
 	// Handle broadcast from server.
-	if res.Message == APIUserOrdersClosed {
-		if cl.HandleOrdersClosed == nil {
-			return nil
-		}
+	ev := newPrivateEvent(res, payload)
 
-		resb, err := base64.StdEncoding.DecodeString(res.Body)
-		if err != nil {
-			log.Printf("handleText: %s %s",
-				APIUserOrdersClosed, err.Error())
-			return nil
+	switch ev.Type {
+	case PrivateEventOrderClosed, PrivateEventOrderCancelled:
+		if cl.HandleOrdersClosed != nil {
+			cl.HandleOrdersClosed(ev.Trade)
 		}
-
-		trade := &Trade{}
-		err = json.Unmarshal(resb, trade)
-		if err != nil {
-			log.Printf("handleText: %s %s",
-				APIUserOrdersClosed, err.Error())
-			return nil
+	case PrivateEventOrderTaken:
+		if cl.HandleOrdersTaken != nil {
+			cl.HandleOrdersTaken(ev.Trade)
 		}
-		cl.HandleOrdersClosed(trade)
 	}
 
-	return nil
+	cl.events.publish(ev)
+}
+
+// heartbeat send the request to server to check if the connection is still
+// alive.
+// Since the private endpoint does not have unauthenticated request, the
+// heartbeat use the APIUserInfo.
+func (cl *WebSocketPrivate) heartbeat() (err error) {
+	_, err = cl.requests.do(cl.conn, cl.opts.HeartbeatInterval,
+		http.MethodGet, APIUserInfo, nil)
+	return err
 }
 
+// handleUnexpectedQuit called by websocket.Client when the connection is
+// closed.
+// Since the websocket.Client still hold the lock, the reconnect must be run
+// in another goroutine.
 func (cl *WebSocketPrivate) handleUnexpectedQuit() {
+	go cl.reconnect()
+}
+
+// reconnect to the server until success.
+func (cl *WebSocketPrivate) reconnect() {
 	log.Println("handleUnexpectedQuit: disconnected ...")
-	for {
-		err := cl.connect()
-		if err != nil {
-			log.Printf("Connect: %s", err.Error())
-			time.Sleep(5 * time.Second)
-			continue
-		}
-		break
+	cl.isConnected.Store(false)
+	cl.requests.failAll()
+
+	err := cl.opts.reconnect(cl.connect, cl.chClosed)
+	if err != nil {
+		// The client is closed or the reconnect policy give up.
+		cl.shutdown()
+		return
 	}
 	log.Println("handleUnexpectedQuit: reconnected ...")
-}
 
-func (cl *WebSocketPrivate) requestPush(req *websocket.Request) (
-	chres chan *websocket.Response,
-) {
-	chres = make(chan *websocket.Response, 1)
-	cl.requestsLocker.Lock()
-	cl.requests[req.ID] = chres
-	cl.requestsLocker.Unlock()
-	return chres
+	cl.watchdog.reset()
 }
 
-func (cl *WebSocketPrivate) requestPop(id uint64) (
-	chres chan *websocket.Response,
-) {
-	cl.requestsLocker.Lock()
-	chres, ok := cl.requests[id]
-	if ok {
-		delete(cl.requests, id)
+// shutdown mark the client as closed and stop the reconnect.
+// It return true if the client is not closed before.
+func (cl *WebSocketPrivate) shutdown() bool {
+	if cl.isClosed.Swap(true) {
+		return false
 	}
-	cl.requestsLocker.Unlock()
-	return chres
+	close(cl.chClosed)
+	return true
 }
@@ -8,29 +8,34 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
-	"time"
+	"sync/atomic"
 
 	"github.com/shuLhan/share/lib/websocket"
 )
 
-const (
-	maxQueue int = 256
-)
-
 // WebSocketPublic define a WebSocket client for public APIs.
 type WebSocketPublic struct {
 	env  *Environment
 	conn *websocket.Client
+	opts *WebSocketOptions
 	subs *PublicSubscription
 
-	requests    map[uint64]chan *websocket.Response
-	topicTrades chan Trade
-	topicDepths chan MarketDepths
+	// chClosed is closed when the client is closed by user or stop
+	// reconnecting.
+	chClosed chan struct{}
+
+	requests         *wsRequests
+	watchdog         *wsWatchdog
+	queueTrades      *topicQueue[Trade]
+	queueDepths      *topicQueue[MarketDepths]
+	queueTicker      *topicQueue[MarketTicker]
+	queueSummaries   *topicQueue[MarketSummaries]
+	topicReconnected chan *PublicSubscription
 
 	// NotifTrades is a channel that will receive public order books
 	// (open, closed, cancelled order) after calling SubscribeTrades
@@ -38,12 +43,68 @@ type WebSocketPublic struct {
 	NotifTrades <-chan Trade
 	NotifDepths <-chan MarketDepths
 
-	requestsLocker sync.Mutex
+	// NotifTicker is a channel that will receive the latest ticker of
+	// pair after calling SubscribeTicker method.
+	NotifTicker <-chan MarketTicker
+
+	// NotifSummaries is a channel that will receive the snapshot of all
+	// pair's summaries after calling SubscribeSummaries method.
+	NotifSummaries <-chan MarketSummaries
+
+	// NotifReconnected is a channel that will receive the restored
+	// subscription after the client reconnected to server.
+	// Since the broadcast messages during disconnected are lost, the
+	// consumer should resynchronize their states, for example by fetching
+	// the MarketDepths.
+	// The channel has one buffer, if the previous event is not consumed
+	// yet, the new event will be dropped.
+	NotifReconnected <-chan *PublicSubscription
+
+	subsManager atomic.Pointer[SubscriptionManager]
+
+	subsLocker  sync.Mutex
+	isConnected atomic.Bool
+	isClosed    atomic.Bool
 }
 
 // NewWebSocketPublic create new WebSocket connection to public APIs.
 func NewWebSocketPublic(env *Environment) (
 	cl *WebSocketPublic, err error,
+) {
+	return NewWebSocketPublicWithOptions(env, nil)
+}
+
+// NewWebSocketPublicWithOptions create new WebSocket connection to public
+// APIs with custom options.
+// If opts is nil, it will use the default options.
+func NewWebSocketPublicWithOptions(env *Environment, opts *WebSocketOptions) (
+	cl *WebSocketPublic, err error,
+) {
+	cl = newWebSocketPublic(env, opts)
+
+	err = cl.connectFirst()
+	if err != nil {
+		return nil, fmt.Errorf("NewWebSocketPublic: %w", err)
+	}
+
+	go cl.watchdog.run(cl.heartbeat, cl.IsConnected, cl.conn.Quit,
+		cl.chClosed)
+
+	return cl, nil
+}
+
+// NewWebSocketPublicReplay create new WebSocketPublic that is not
+// connected to server, to receive the recorded frames from
+// SessionReplayer.
+// If opts is nil, it will use the default options.
+func NewWebSocketPublicReplay(opts *WebSocketOptions) (cl *WebSocketPublic) {
+	return newWebSocketPublic(nil, opts)
+}
+
+// newWebSocketPublic create and initialize the client without connecting
+// to server.
+func newWebSocketPublic(env *Environment, opts *WebSocketOptions) (
+	cl *WebSocketPublic,
 ) {
 	if env == nil {
 		env = NewEnvironment("", "")
@@ -51,20 +112,44 @@ func NewWebSocketPublic(env *Environment) (
 	if len(env.Address) == 0 {
 		env.Address = DefaultAddress
 	}
+	opts = opts.init(WSPublic)
 
 	cl = &WebSocketPublic{
 		env: env,
 		conn: &websocket.Client{
 			Headers: make(http.Header),
 		},
-		requests:    make(map[uint64](chan *websocket.Response)),
-		subs:        &PublicSubscription{},
-		topicTrades: make(chan Trade, maxQueue),
-		topicDepths: make(chan MarketDepths, maxQueue),
-	}
-
-	cl.NotifTrades = cl.topicTrades
-	cl.NotifDepths = cl.topicDepths
+		opts:     opts,
+		chClosed: make(chan struct{}),
+		requests: newWSRequests(),
+		watchdog: newWSWatchdog(opts),
+		subs:     &PublicSubscription{},
+
+		topicReconnected: make(chan *PublicSubscription, 1),
+	}
+
+	cl.queueTrades = newTopicQueue(opts.QueueTrades,
+		func(trade Trade) string {
+			return strconv.FormatInt(trade.ID, 10)
+		}, nil, cl.chClosed)
+	cl.queueDepths = newTopicQueue(opts.QueueDepths,
+		func(depths MarketDepths) string {
+			return depths.Pair
+		}, coalesceDepths, cl.chClosed)
+	cl.queueTicker = newTopicQueue(opts.QueueTicker,
+		func(tick MarketTicker) string {
+			return tick.PairName
+		}, nil, cl.chClosed)
+	cl.queueSummaries = newTopicQueue(opts.QueueSummaries,
+		func(MarketSummaries) string {
+			return TopicSummaries
+		}, nil, cl.chClosed)
+
+	cl.NotifTrades = cl.queueTrades.out
+	cl.NotifDepths = cl.queueDepths.out
+	cl.NotifTicker = cl.queueTicker.out
+	cl.NotifSummaries = cl.queueSummaries.out
+	cl.NotifReconnected = cl.topicReconnected
 
 	if env.IsInsecure {
 		cl.conn.TLSConfig = &tls.Config{
@@ -72,28 +157,50 @@ func NewWebSocketPublic(env *Environment) (
 		}
 	}
 
+	cl.requests.recorder = opts.Recorder
+	cl.requests.endpoint = cl.endpoint()
+
 	cl.conn.HandleText = cl.handleText
 	cl.conn.HandleQuit = cl.handleUnexpectedQuit
 
-	err = cl.connect()
-	if err != nil {
-		return nil, fmt.Errorf("NewWebSocketPublic: %w", err)
-	}
-
-	return cl, nil
+	return cl
 }
 
 // Close the connection and release all the resource.
 func (cl *WebSocketPublic) Close() error {
-	cl.requestsLocker.Lock()
-	for id, ch := range cl.requests {
-		ch <- nil
-		close(ch)
-		delete(cl.requests, id)
+	isClosing := cl.shutdown()
+
+	cl.requests.failAll()
+
+	cl.isConnected.Store(false)
+
+	err := cl.conn.Close()
+	if isClosing {
+		cl.opts.emit(WebSocketStateClosed, 0, nil)
 	}
-	cl.requestsLocker.Unlock()
+	return err
+}
+
+// IsConnected return true if the client is currently connected to server.
+func (cl *WebSocketPublic) IsConnected() bool {
+	return cl.isConnected.Load()
+}
 
-	return cl.conn.Close()
+// QueueStats return the counters of notification queue on topic
+// TopicDepths, TopicSummaries, TopicTicker, or TopicTrades.
+// It return empty QueueStats if the topic is unknown.
+func (cl *WebSocketPublic) QueueStats(topic string) (qstats QueueStats) {
+	switch topic {
+	case TopicDepths:
+		return cl.queueDepths.stats()
+	case TopicSummaries:
+		return cl.queueSummaries.stats()
+	case TopicTicker:
+		return cl.queueTicker.stats()
+	case TopicTrades:
+		return cl.queueTrades.stats()
+	}
+	return qstats
 }
 
 // MarketDepths fetch list of market's depth for specific pair.
@@ -125,6 +232,21 @@ func (cl *WebSocketPublic) MarketDepths(pair string) (
 	return depths, nil
 }
 
+// MarketInfo return information about all the pair in the platform.
+func (cl *WebSocketPublic) MarketInfo() (marketInfos []MarketInfo, err error) {
+	_, resbody, err := cl.send(http.MethodGet, APIMarketInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(resbody, &marketInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	return marketInfos, nil
+}
+
 // MarketPrices fetch the latest pair price from the market.
 func (cl *WebSocketPublic) MarketPrices() (mprices MarketPrices, err error) {
 	_, resbody, err := cl.send(http.MethodGet, APIMarketPrices, nil)
@@ -218,6 +340,36 @@ func (cl *WebSocketPublic) MarketTrades(pair string, offset, limit int64) (
 	return marketTrades, nil
 }
 
+// MarketTradesOpen return list of all open trades in the market, specific to
+// pair's name, grouped by ask and bid.
+func (cl *WebSocketPublic) MarketTradesOpen(pair string) (
+	openTrades *TradesOpen, err error,
+) {
+	if len(pair) == 0 {
+		return nil, ErrInvalidPair
+	}
+
+	wsparams := &WebSocketParams{
+		TradeRequest: TradeRequest{
+			Pair: pair,
+		},
+	}
+
+	_, resbody, err := cl.send(http.MethodGet, APIMarketTradesOpen, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	openTrades = &TradesOpen{}
+
+	err = json.Unmarshal(resbody, openTrades)
+	if err != nil {
+		return nil, err
+	}
+
+	return openTrades, nil
+}
+
 // Subscription return the list and status of subscription.
 func (cl *WebSocketPublic) Subscription() (*PublicSubscription, error) {
 	_, resbody, err := cl.send(http.MethodGet, WSPublicSubscription, nil)
@@ -225,7 +377,7 @@ func (cl *WebSocketPublic) Subscription() (*PublicSubscription, error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(resbody, cl.subs)
+	err = cl.subsUpdate(resbody)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +410,63 @@ func (cl *WebSocketPublic) SubscribeDepths(pairNames []string) (
 		return nil, err
 	}
 
-	err = json.Unmarshal(resbody, cl.subs)
+	err = cl.subsUpdate(resbody)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.subs, nil
+}
+
+// SubscribeSummaries subscribe to changes on market summaries.
+//
+// The snapshot of market summaries can be retrieved from NotifSummaries
+// field.
+func (cl *WebSocketPublic) SubscribeSummaries() (*PublicSubscription, error) {
+	wsparams := &WebSocketParams{
+		PublicSubscription: PublicSubscription{
+			Summaries: true,
+		},
+	}
+
+	_, resbody, err := cl.send(http.MethodPost, WSPublicSubscription, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cl.subsUpdate(resbody)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.subs, nil
+}
+
+// SubscribeTicker subscribe to changes on pair's ticker based on list of
+// pair names.
+//
+// Multiple calls on this method will not clear previously subscribed pairs.
+//
+// The latest ticker of each pair can be retrieved from NotifTicker field.
+func (cl *WebSocketPublic) SubscribeTicker(pairNames []string) (
+	*PublicSubscription, error,
+) {
+	if len(pairNames) == 0 {
+		return cl.subs, nil
+	}
+
+	wsparams := &WebSocketParams{
+		PublicSubscription: PublicSubscription{
+			Ticker: pairNames,
+		},
+	}
+
+	_, resbody, err := cl.send(http.MethodPost, WSPublicSubscription, wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cl.subsUpdate(resbody)
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +502,7 @@ func (cl *WebSocketPublic) SubscribeTrades(pairNames []string) (
 		return nil, err
 	}
 
-	err = json.Unmarshal(resbody, cl.subs)
+	err = cl.subsUpdate(resbody)
 	if err != nil {
 		return nil, err
 	}
@@ -312,12 +520,12 @@ func (cl *WebSocketPublic) UnsubscribeDepths(pairNames []string) (
 	*PublicSubscription, error,
 ) {
 	if len(pairNames) == 0 {
-		pairNames = cl.subs.Trades
+		pairNames = cl.subsCopy().Depths
 	}
 
 	wsparams := &WebSocketParams{
 		PublicSubscription: PublicSubscription{
-			Trades: pairNames,
+			Depths: pairNames,
 		},
 	}
 
@@ -326,7 +534,64 @@ func (cl *WebSocketPublic) UnsubscribeDepths(pairNames []string) (
 		return nil, err
 	}
 
-	err = json.Unmarshal(resbody, cl.subs)
+	err = cl.subsUpdate(resbody)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.subs, nil
+}
+
+// UnsubscribeSummaries stop receiving broadcast notification on topic
+// "summaries".
+//
+// On success it will return the latest subscription.
+func (cl *WebSocketPublic) UnsubscribeSummaries() (*PublicSubscription, error) {
+	wsparams := &WebSocketParams{
+		PublicSubscription: PublicSubscription{
+			Summaries: true,
+		},
+	}
+
+	_, resbody, err := cl.send(http.MethodDelete, WSPublicSubscription,
+		wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cl.subsUpdate(resbody)
+	if err != nil {
+		return nil, err
+	}
+
+	return cl.subs, nil
+}
+
+// UnsubscribeTicker stop receiving broadcast notification on topic
+// "ticker" on specific pairs.
+// If parameter is empty, it will unsubscribe all registered pairs.
+//
+// On success it will return the latest subscription.
+func (cl *WebSocketPublic) UnsubscribeTicker(pairNames []string) (
+	*PublicSubscription, error,
+) {
+	if len(pairNames) == 0 {
+		pairNames = cl.subsCopy().Ticker
+	}
+
+	wsparams := &WebSocketParams{
+		PublicSubscription: PublicSubscription{
+			Ticker: pairNames,
+		},
+	}
+
+	_, resbody, err := cl.send(http.MethodDelete, WSPublicSubscription,
+		wsparams)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cl.subsUpdate(resbody)
 	if err != nil {
 		return nil, err
 	}
@@ -343,7 +608,7 @@ func (cl *WebSocketPublic) UnsubscribeTrades(pairNames []string) (
 	*PublicSubscription, error,
 ) {
 	if len(pairNames) == 0 {
-		pairNames = cl.subs.Trades
+		pairNames = cl.subsCopy().Trades
 	}
 
 	wsparams := &WebSocketParams{
@@ -358,7 +623,7 @@ func (cl *WebSocketPublic) UnsubscribeTrades(pairNames []string) (
 		return nil, err
 	}
 
-	err = json.Unmarshal(resbody, cl.subs)
+	err = cl.subsUpdate(resbody)
 	if err != nil {
 		return nil, err
 	}
@@ -366,6 +631,21 @@ func (cl *WebSocketPublic) UnsubscribeTrades(pairNames []string) (
 	return cl.subs, nil
 }
 
+// connectFirst connect to server for the first time.
+func (cl *WebSocketPublic) connectFirst() (err error) {
+	cl.opts.emit(WebSocketStateConnecting, 0, nil)
+
+	err = cl.connect()
+	if err != nil {
+		cl.shutdown()
+		cl.opts.emit(WebSocketStateClosed, 0, err)
+		return err
+	}
+
+	cl.opts.emit(WebSocketStateConnected, 0, nil)
+	return nil
+}
+
 func (cl *WebSocketPublic) connect() (err error) {
 	cl.conn.Endpoint = cl.env.Address + WSPublic
 
@@ -374,6 +654,8 @@ func (cl *WebSocketPublic) connect() (err error) {
 		return fmt.Errorf("connect: %w", err)
 	}
 
+	cl.isConnected.Store(true)
+
 	return nil
 }
 
@@ -382,15 +664,35 @@ func (cl *WebSocketPublic) handleText(
 ) (
 	err error,
 ) {
-	var (
-		res     = &websocket.Response{}
-		payload = frame.Payload()
-	)
+	payload := frame.Payload()
+
+	if cl.opts.Recorder != nil {
+		cl.opts.Recorder.recordIn(cl.endpoint(), payload)
+	}
+
+	cl.handlePayload(payload)
+
+	return nil
+}
 
-	err = json.Unmarshal(payload, res)
+// endpoint return the WebSocket endpoint of client, used by
+// SessionRecorder to label the frames and by SessionReplayer to filter
+// them.
+func (cl *WebSocketPublic) endpoint() string {
+	return WSPublic
+}
+
+// handlePayload handle the text payload received from server, either
+// as response of request or as broadcast.
+func (cl *WebSocketPublic) handlePayload(payload []byte) {
+	res := &websocket.Response{}
+
+	cl.watchdog.touch()
+
+	err := json.Unmarshal(payload, res)
 	if err != nil {
 		log.Printf("handleText: %q: %s", payload, err.Error())
-		return nil
+		return
 	}
 
 	if res.ID == 0 {
@@ -398,7 +700,7 @@ func (cl *WebSocketPublic) handleText(
 		if err != nil {
 			log.Printf("handleText: broadcast %s: %s",
 				res.Message, err)
-			return nil
+			return
 		}
 
 		switch res.Message {
@@ -408,64 +710,160 @@ func (cl *WebSocketPublic) handleText(
 			if err != nil {
 				log.Printf("handleText: broadcast %s: %s",
 					res.Message, err)
-				return nil
+				return
 			}
-			cl.topicTrades <- trade
+			cl.watchdog.touchTopic(TopicTrades, trade.Pair)
+			cl.queueTrades.push(trade)
 		case APIMarketDepths:
 			depths := MarketDepths{}
 			err = json.Unmarshal(resbody, &depths)
 			if err != nil {
 				log.Printf("handleText: broadcast %s: %s",
 					res.Message, err)
-				return nil
+				return
+			}
+			cl.watchdog.touchTopic(TopicDepths, depths.Pair)
+			cl.queueDepths.push(depths)
+		case APIMarketTicker:
+			tick := MarketTicker{}
+			err = json.Unmarshal(resbody, &tick)
+			if err != nil {
+				log.Printf("handleText: broadcast %s: %s",
+					res.Message, err)
+				return
 			}
-			cl.topicDepths <- depths
+			cl.watchdog.touchTopic(TopicTicker, tick.PairName)
+			cl.queueTicker.push(tick)
+		case APIMarketSummaries:
+			summaries := MarketSummaries{}
+			err = json.Unmarshal(resbody, &summaries)
+			if err != nil {
+				log.Printf("handleText: broadcast %s: %s",
+					res.Message, err)
+				return
+			}
+			cl.watchdog.touchTopic(TopicSummaries, "")
+			cl.queueSummaries.push(summaries)
 		}
 	} else {
-		chres := cl.requestPop(res.ID)
+		chres := cl.requests.pop(res.ID)
 		if chres != nil {
 			chres <- res
 		}
-		return nil
 	}
+}
 
-	return nil
+// heartbeat send the request to server to check if the connection is still
+// alive.
+func (cl *WebSocketPublic) heartbeat() (err error) {
+	_, err = cl.requests.do(cl.conn, cl.opts.HeartbeatInterval,
+		http.MethodGet, WSPublicSubscription, nil)
+	return err
 }
 
+// handleUnexpectedQuit called by websocket.Client when the connection is
+// closed.
+// Since the websocket.Client still hold the lock, the reconnect must be run
+// in another goroutine.
 func (cl *WebSocketPublic) handleUnexpectedQuit() {
+	go cl.reconnect()
+}
+
+// reconnect to the server until success and then restore the previous
+// subscription.
+func (cl *WebSocketPublic) reconnect() {
 	log.Println("handleUnexpectedQuit: disconnected ...")
-	for {
-		err := cl.connect()
-		if err != nil {
-			log.Printf("connect: %s", err.Error())
-			time.Sleep(5 * time.Second)
-			continue
-		}
-		break
+	cl.isConnected.Store(false)
+	cl.requests.failAll()
+
+	err := cl.opts.reconnect(cl.connect, cl.chClosed)
+	if err != nil {
+		// The client is closed or the reconnect policy give up.
+		cl.shutdown()
+		return
 	}
 	log.Println("handleUnexpectedQuit: reconnected ...")
+
+	cl.watchdog.reset()
+
+	subs, err := cl.resubscribe()
+	if err != nil {
+		log.Printf("handleUnexpectedQuit: resubscribe: %s", err)
+	}
+
+	select {
+	case cl.topicReconnected <- subs:
+	default:
+	}
 }
 
-func (cl *WebSocketPublic) requestPush(req *websocket.Request) (
-	chres chan *websocket.Response,
-) {
-	chres = make(chan *websocket.Response, 1)
-	cl.requestsLocker.Lock()
-	cl.requests[req.ID] = chres
-	cl.requestsLocker.Unlock()
-	return chres
+// resubscribe restore all of the previous subscription in new connection.
+// If the client is managed by SubscriptionManager, the desired subscription
+// in the manager is restored instead.
+func (cl *WebSocketPublic) resubscribe() (subs *PublicSubscription, err error) {
+	prev := cl.subsCopy()
+
+	sm := cl.subsManager.Load()
+	if sm != nil {
+		prev = sm.Desired()
+	}
+
+	if prev.isEmpty() {
+		return prev, nil
+	}
+
+	err = cl.subscription(http.MethodPost, prev)
+	if err != nil {
+		return prev, err
+	}
+
+	return cl.subsCopy(), nil
+}
+
+// shutdown mark the client as closed and stop the reconnect.
+// It return true if the client is not closed before.
+func (cl *WebSocketPublic) shutdown() bool {
+	if cl.isClosed.Swap(true) {
+		return false
+	}
+	close(cl.chClosed)
+	return true
 }
 
-func (cl *WebSocketPublic) requestPop(id uint64) (
-	chres chan *websocket.Response,
+// subscription send the request to subscribe (POST) or unsubscribe
+// (DELETE) to all topics in subs at once.
+func (cl *WebSocketPublic) subscription(method string, subs *PublicSubscription) (
+	err error,
 ) {
-	cl.requestsLocker.Lock()
-	chres, ok := cl.requests[id]
-	if ok {
-		delete(cl.requests, id)
+	wsparams := &WebSocketParams{
+		PublicSubscription: *subs,
+	}
+
+	_, resbody, err := cl.send(method, WSPublicSubscription, wsparams)
+	if err != nil {
+		return err
+	}
+
+	return cl.subsUpdate(resbody)
+}
+
+// subsCopy return the copy of current subscription.
+func (cl *WebSocketPublic) subsCopy() (subs *PublicSubscription) {
+	cl.subsLocker.Lock()
+	subs = cl.subs.clone()
+	cl.subsLocker.Unlock()
+	return subs
+}
+
+// subsUpdate update the current subscription from the response body.
+func (cl *WebSocketPublic) subsUpdate(resbody []byte) (err error) {
+	cl.subsLocker.Lock()
+	err = json.Unmarshal(resbody, cl.subs)
+	if err == nil {
+		cl.watchdog.track(cl.subs)
 	}
-	cl.requestsLocker.Unlock()
-	return chres
+	cl.subsLocker.Unlock()
+	return err
 }
 
 func (cl *WebSocketPublic) send(
@@ -482,32 +880,11 @@ func (cl *WebSocketPublic) send(
 		}
 	}
 
-	req := &websocket.Request{
-		ID:     uint64(time.Now().UnixNano()),
-		Method: method,
-		Target: target,
-		Body:   base64.StdEncoding.EncodeToString(body),
-	}
-
-	payload, err := json.Marshal(req)
+	res, err = cl.requests.do(cl.conn, cl.opts.Timeout, method, target, body)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	chres := cl.requestPush(req)
-
-	err = cl.conn.SendText(payload)
-	if err != nil {
-		cl.requestPop(req.ID)
-		return nil, nil, err
-	}
-
-	res = <-chres
-
-	if res.Code != http.StatusOK {
-		return nil, nil, errors.New(res.Message)
-	}
-
 	resbody, err = base64.StdEncoding.DecodeString(res.Body)
 	if err != nil {
 		return res, resbody, err
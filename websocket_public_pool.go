@@ -0,0 +1,418 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPublicPoolSize define the default number of connections in
+// WebSocketPublicPool.
+const DefaultPublicPoolSize = 4
+
+// WebSocketPublicPool spread the public subscriptions over several
+// WebSocketPublic connections by pair, and merge their notifications into
+// single set of channels.
+//
+// All topics of the same pair are subscribed on the same connection, so
+// the messages for each pair are received in order.
+// The topic summaries is subscribed on the first connection.
+//
+// Each pair is assigned to the connection with the least pairs.
+// When the pairs are added or removed, the pool keep the existing
+// assignment and only move the pairs from the busiest connection until
+// the number of pairs on each connection differ at most by one.
+// The moved pair is subscribed on the new connection before unsubscribed
+// from the old one, so the consumer may receive duplicate messages during
+// rebalance but not a gap.
+//
+// The subscription on each connection is managed by SubscriptionManager,
+// so it is restored after reconnect.
+// The application should not read the notification channels or manage
+// the subscription of each connection directly.
+type WebSocketPublicPool struct {
+	// NotifDepths, NotifTicker, NotifTrades, and NotifSummaries merge
+	// the notifications from all connections.
+	// The queue policy on each connection, defined in
+	// WebSocketOptions, apply when the consumer is slower than the
+	// incoming messages.
+	NotifDepths    <-chan MarketDepths
+	NotifTicker    <-chan MarketTicker
+	NotifTrades    <-chan Trade
+	NotifSummaries <-chan MarketSummaries
+
+	// NotifReconnected receive the subscription of each connection
+	// after its reconnected, so the consumer can resynchronize the
+	// states of those pairs.
+	NotifReconnected <-chan *PublicSubscription
+
+	desired *PublicSubscription
+
+	// shards map the pair name to the index of connection.
+	shards map[string]int
+
+	// chClosed is closed when the pool is closed.
+	chClosed chan struct{}
+
+	conns []*WebSocketPublic
+	sms   []*SubscriptionManager
+
+	locker   sync.Mutex
+	isClosed atomic.Bool
+}
+
+// NewWebSocketPublicPool open the size number of connections to public
+// APIs.
+// If size is less or equal to zero, it will use DefaultPublicPoolSize.
+// If opts is nil, it will use the default options.
+func NewWebSocketPublicPool(
+	env *Environment, size int, opts *WebSocketOptions,
+) (
+	pool *WebSocketPublicPool, err error,
+) {
+	if size <= 0 {
+		size = DefaultPublicPoolSize
+	}
+	var (
+		chDepths      = make(chan MarketDepths, DefaultQueueCapacity)
+		chTicker      = make(chan MarketTicker, DefaultQueueCapacity)
+		chTrades      = make(chan Trade, DefaultQueueCapacity)
+		chSummaries   = make(chan MarketSummaries, DefaultQueueCapacity)
+		chReconnected = make(chan *PublicSubscription, size)
+	)
+
+	pool = &WebSocketPublicPool{
+		NotifDepths:      chDepths,
+		NotifTicker:      chTicker,
+		NotifTrades:      chTrades,
+		NotifSummaries:   chSummaries,
+		NotifReconnected: chReconnected,
+
+		desired:  &PublicSubscription{},
+		shards:   make(map[string]int),
+		chClosed: make(chan struct{}),
+	}
+
+	for x := 0; x < size; x++ {
+		ws, err := NewWebSocketPublicWithOptions(env, opts)
+		if err != nil {
+			_ = pool.Close()
+			return nil, fmt.Errorf("NewWebSocketPublicPool: %w", err)
+		}
+
+		pool.conns = append(pool.conns, ws)
+		pool.sms = append(pool.sms, NewSubscriptionManager(ws))
+
+		go poolForward(ws.NotifDepths, chDepths, pool.chClosed)
+		go poolForward(ws.NotifTicker, chTicker, pool.chClosed)
+		go poolForward(ws.NotifTrades, chTrades, pool.chClosed)
+		go poolForward(ws.NotifSummaries, chSummaries, pool.chClosed)
+		go poolForward(ws.NotifReconnected, chReconnected, pool.chClosed)
+	}
+
+	return pool, nil
+}
+
+// Close all connections in the pool.
+func (pool *WebSocketPublicPool) Close() (err error) {
+	if pool.isClosed.Swap(true) {
+		return nil
+	}
+	close(pool.chClosed)
+
+	// Close all connections and return the first error, if any.
+	for _, ws := range pool.conns {
+		errClose := ws.Close()
+		if errClose != nil && err == nil {
+			err = errClose
+		}
+	}
+	return err
+}
+
+// Desired return the copy of desired subscription on all connections.
+func (pool *WebSocketPublicPool) Desired() (subs *PublicSubscription) {
+	pool.locker.Lock()
+	subs = pool.desired.clone()
+	pool.locker.Unlock()
+	return subs
+}
+
+// Set replace the desired subscription on all topics, rebalance the pairs
+// over connections, and apply it to server.
+// On success it will return the union of latest subscription reported
+// by server on all connections.
+// On failure, the desired subscription and the pair assignment are not
+// changed, and the subscription on each connection is restored.
+func (pool *WebSocketPublicPool) Set(desired *PublicSubscription) (
+	*PublicSubscription, error,
+) {
+	if desired == nil {
+		desired = &PublicSubscription{}
+	}
+
+	pool.locker.Lock()
+	defer pool.locker.Unlock()
+
+	return pool.apply(desired.clone())
+}
+
+// SetSummaries set the desired subscription on topic "summaries" and
+// apply it to server.
+func (pool *WebSocketPublicPool) SetSummaries(isSubscribe bool) (
+	*PublicSubscription, error,
+) {
+	pool.locker.Lock()
+	defer pool.locker.Unlock()
+
+	desired := pool.desired.clone()
+	desired.Summaries = isSubscribe
+
+	return pool.apply(desired)
+}
+
+// SetTopic replace the desired pairs on topic TopicDepths, TopicTicker, or
+// TopicTrades, rebalance the pairs over connections, and apply it to
+// server.
+// Empty pairNames means unsubscribe all pairs on the topic.
+func (pool *WebSocketPublicPool) SetTopic(topic string, pairNames []string) (
+	*PublicSubscription, error,
+) {
+	pairNames = append([]string(nil), pairNames...)
+
+	pool.locker.Lock()
+	defer pool.locker.Unlock()
+
+	desired := pool.desired.clone()
+
+	switch topic {
+	case TopicDepths:
+		desired.Depths = pairNames
+	case TopicTicker:
+		desired.Ticker = pairNames
+	case TopicTrades:
+		desired.Trades = pairNames
+	default:
+		return nil, ErrInvalidTopic
+	}
+
+	return pool.apply(desired)
+}
+
+// Shard return the index of connection where the pair is subscribed, or
+// -1 if the pair is not subscribed.
+func (pool *WebSocketPublicPool) Shard(pairName string) int {
+	pool.locker.Lock()
+	defer pool.locker.Unlock()
+
+	x, ok := pool.shards[pairName]
+	if !ok {
+		return -1
+	}
+	return x
+}
+
+// Size return the number of connections in the pool.
+func (pool *WebSocketPublicPool) Size() int {
+	return len(pool.conns)
+}
+
+// apply rebalance the desired pairs and set the subscription on each
+// connection.
+// The desired and the new pair assignment are stored in the pool only if
+// all connections success, otherwise the previous subscription on each
+// connection is restored.
+// The caller must hold the lock.
+func (pool *WebSocketPublicPool) apply(desired *PublicSubscription) (
+	current *PublicSubscription, err error,
+) {
+	if pool.isClosed.Load() {
+		return nil, errClosed
+	}
+
+	var (
+		logp   = "WebSocketPublicPool"
+		shards = poolAssign(pool.shards, poolPairs(desired),
+			len(pool.conns))
+		subss = poolSplit(desired, shards, len(pool.conns))
+		prevs = make([]*PublicSubscription, len(pool.sms))
+	)
+	for x, sm := range pool.sms {
+		prevs[x] = sm.Desired()
+	}
+
+	// Subscribe the new pairs on each connection first, before
+	// unsubscribing the moved pairs from the previous connection.
+	for x, sm := range pool.sms {
+		add, _ := diffSubscription(subss[x], prevs[x])
+		if add.isEmpty() {
+			continue
+		}
+		_, err = sm.Set(poolMerge(prevs[x], add))
+		if err != nil {
+			pool.rollback(prevs)
+			return nil, fmt.Errorf("%s: connection %d: %w", logp, x, err)
+		}
+	}
+
+	current = &PublicSubscription{}
+	for x, sm := range pool.sms {
+		subs, err := sm.Set(subss[x])
+		if err != nil {
+			pool.rollback(prevs)
+			return nil, fmt.Errorf("%s: connection %d: %w", logp, x, err)
+		}
+		current = poolMerge(current, subs)
+	}
+
+	pool.desired = desired
+	pool.shards = shards
+
+	return current, nil
+}
+
+// rollback restore the subscription on each connection that has been
+// changed by apply.
+// The error is ignored, since the previous desired subscription is kept
+// by SubscriptionManager and restored on the next reconnect.
+// The caller must hold the lock.
+func (pool *WebSocketPublicPool) rollback(prevs []*PublicSubscription) {
+	for x, sm := range pool.sms {
+		add, del := diffSubscription(sm.Desired(), prevs[x])
+		if add.isEmpty() && del.isEmpty() {
+			continue
+		}
+		_, _ = sm.Set(prevs[x])
+	}
+}
+
+// poolAssign assign each pair to one of n connections.
+// The pair that has been assigned in prev keep its connection, the new
+// pair is assigned to the connection with the least pairs, and then the
+// pairs are moved from the busiest connection into the least one until
+// their number of pairs differ at most by one.
+func poolAssign(prev map[string]int, pairNames []string, n int) (
+	shards map[string]int,
+) {
+	var (
+		loads = make([][]string, n)
+		added []string
+	)
+
+	for _, pair := range pairNames {
+		x, ok := prev[pair]
+		if ok && x < n {
+			loads[x] = append(loads[x], pair)
+		} else {
+			added = append(added, pair)
+		}
+	}
+
+	sort.Strings(added)
+	for _, pair := range added {
+		min, _ := poolMinMax(loads)
+		loads[min] = append(loads[min], pair)
+	}
+
+	for {
+		min, max := poolMinMax(loads)
+		if len(loads[max])-len(loads[min]) <= 1 {
+			break
+		}
+		sort.Strings(loads[max])
+		last := len(loads[max]) - 1
+		loads[min] = append(loads[min], loads[max][last])
+		loads[max] = loads[max][:last]
+	}
+
+	shards = make(map[string]int, len(pairNames))
+	for x, pairs := range loads {
+		for _, pair := range pairs {
+			shards[pair] = x
+		}
+	}
+	return shards
+}
+
+// poolMinMax return the index of connections with the least and the most
+// pairs.
+// If there are more than one, the lowest index is returned.
+func poolMinMax(loads [][]string) (min, max int) {
+	for x := 1; x < len(loads); x++ {
+		if len(loads[x]) < len(loads[min]) {
+			min = x
+		}
+		if len(loads[x]) > len(loads[max]) {
+			max = x
+		}
+	}
+	return min, max
+}
+
+// poolPairs return the sorted unique pairs from all topics in subs.
+func poolPairs(subs *PublicSubscription) []string {
+	pairs := make(map[string]struct{})
+	setAdd(pairs, subs.Depths)
+	setAdd(pairs, subs.Ticker)
+	setAdd(pairs, subs.Trades)
+	return setSlice(pairs)
+}
+
+// poolSplit split the subscription into n subscriptions based on the pair
+// assignment in shards.
+// The topic summaries is assigned to the first subscription.
+func poolSplit(subs *PublicSubscription, shards map[string]int, n int) (
+	subss []*PublicSubscription,
+) {
+	subss = make([]*PublicSubscription, n)
+	for x := range subss {
+		subss[x] = &PublicSubscription{}
+	}
+	for _, pair := range subs.Depths {
+		x := shards[pair]
+		subss[x].Depths = append(subss[x].Depths, pair)
+	}
+	for _, pair := range subs.Ticker {
+		x := shards[pair]
+		subss[x].Ticker = append(subss[x].Ticker, pair)
+	}
+	for _, pair := range subs.Trades {
+		x := shards[pair]
+		subss[x].Trades = append(subss[x].Trades, pair)
+	}
+	if n > 0 {
+		subss[0].Summaries = subs.Summaries
+	}
+	return subss
+}
+
+// poolMerge return the union of subscription a and b.
+func poolMerge(a, b *PublicSubscription) (subs *PublicSubscription) {
+	subs = a.clone()
+	subs.Depths = append(subs.Depths, diffPairs(b.Depths, a.Depths)...)
+	subs.Ticker = append(subs.Ticker, diffPairs(b.Ticker, a.Ticker)...)
+	subs.Trades = append(subs.Trades, diffPairs(b.Trades, a.Trades)...)
+	subs.Summaries = a.Summaries || b.Summaries
+	return subs
+}
+
+// poolForward forward the messages from in to out until the pool closed.
+func poolForward[T any](in <-chan T, out chan<- T, chClosed <-chan struct{}) {
+	for {
+		select {
+		case msg := <-in:
+			select {
+			case out <- msg:
+			case <-chClosed:
+				return
+			}
+		case <-chClosed:
+			return
+		}
+	}
+}
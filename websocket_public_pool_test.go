@@ -0,0 +1,104 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestPoolAssign(t *testing.T) {
+	cases := []struct {
+		desc  string
+		prev  map[string]int
+		exp   map[string]int
+		pairs []string
+		n     int
+	}{{
+		desc:  "from empty",
+		pairs: []string{"e", "a", "d", "c", "b"},
+		n:     2,
+		exp: map[string]int{
+			"a": 0, "b": 1, "c": 0, "d": 1, "e": 0,
+		},
+	}, {
+		desc: "add keep the previous assignment",
+		prev: map[string]int{
+			"a": 1, "b": 1,
+		},
+		pairs: []string{"a", "b", "c"},
+		n:     2,
+		exp: map[string]int{
+			"a": 1, "b": 1, "c": 0,
+		},
+	}, {
+		desc: "remove rebalance from the busiest",
+		prev: map[string]int{
+			"a": 0, "b": 0, "c": 0, "d": 1, "e": 1, "f": 1,
+		},
+		pairs: []string{"a", "b", "c"},
+		n:     2,
+		exp: map[string]int{
+			"a": 0, "b": 0, "c": 1,
+		},
+	}, {
+		desc: "shrink connections",
+		prev: map[string]int{
+			"a": 0, "b": 1, "c": 2,
+		},
+		pairs: []string{"a", "b", "c"},
+		n:     2,
+		exp: map[string]int{
+			"a": 0, "b": 1, "c": 0,
+		},
+	}, {
+		desc: "empty pairs",
+		prev: map[string]int{
+			"a": 0,
+		},
+		n:   2,
+		exp: map[string]int{},
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		got := poolAssign(c.prev, c.pairs, c.n)
+
+		test.Assert(t, "shards", c.exp, got)
+	}
+}
+
+func TestPoolSplit(t *testing.T) {
+	var (
+		subs = &PublicSubscription{
+			Depths:    []string{"a", "b"},
+			Ticker:    []string{"b"},
+			Trades:    []string{"a", "c"},
+			Summaries: true,
+		}
+		shards = poolAssign(nil, poolPairs(subs), 2)
+		exp    = []*PublicSubscription{{
+			Depths:    []string{"a"},
+			Trades:    []string{"a", "c"},
+			Summaries: true,
+		}, {
+			Depths: []string{"b"},
+			Ticker: []string{"b"},
+		}}
+	)
+
+	got := poolSplit(subs, shards, 2)
+	test.Assert(t, "poolSplit", exp, got)
+
+	merged := poolMerge(got[0], got[1])
+	test.Assert(t, "poolMerge", &PublicSubscription{
+		Depths:    []string{"a", "b"},
+		Ticker:    []string{"b"},
+		Trades:    []string{"a", "c"},
+		Summaries: true,
+	}, merged)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	liberrors "github.com/shuLhan/share/lib/errors"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+// wsRequests manage the pending requests on WebSocket client, the requests
+// that has been send to server and waiting for response.
+type wsRequests struct {
+	pending  map[uint64]chan *websocket.Response
+	recorder *SessionRecorder
+	endpoint string
+	lastID   atomic.Uint64
+	locker   sync.Mutex
+}
+
+func newWSRequests() (reqs *wsRequests) {
+	reqs = &wsRequests{
+		pending: make(map[uint64]chan *websocket.Response),
+	}
+	// Start the ID from current time, so the ID will not collide with
+	// the requests from previous process.
+	reqs.lastID.Store(uint64(time.Now().UnixNano()))
+	return reqs
+}
+
+// do send the request to server using conn and wait for its response
+// until timeout.
+// If timeout is less or equal to zero, it will wait until the response
+// received or the connection lost.
+func (reqs *wsRequests) do(
+	conn *websocket.Client, timeout time.Duration,
+	method, target string, body []byte,
+) (
+	res *websocket.Response, err error,
+) {
+	req := &websocket.Request{
+		ID:     reqs.lastID.Add(1),
+		Method: method,
+		Target: target,
+		Body:   base64.StdEncoding.EncodeToString(body),
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if reqs.recorder != nil {
+		reqs.recorder.recordOut(reqs.endpoint, req, body)
+	}
+
+	chres := reqs.push(req.ID)
+
+	err = conn.SendText(payload)
+	if err != nil {
+		reqs.pop(req.ID)
+		return nil, err
+	}
+
+	var (
+		timer     *time.Timer
+		chTimeout <-chan time.Time
+		ok        bool
+	)
+	if timeout > 0 {
+		timer = time.NewTimer(timeout)
+		defer timer.Stop()
+		chTimeout = timer.C
+	}
+
+	select {
+	case res, ok = <-chres:
+		if !ok {
+			return nil, ErrWebSocketDisconnected
+		}
+	case <-chTimeout:
+		reqs.pop(req.ID)
+		return nil, ErrWebSocketTimeout
+	}
+
+	if res.Code != http.StatusOK {
+		return nil, &liberrors.E{
+			Code:    int(res.Code),
+			Message: res.Message,
+		}
+	}
+
+	return res, nil
+}
+
+// failAll fail all of the pending requests with ErrWebSocketDisconnected.
+func (reqs *wsRequests) failAll() {
+	reqs.locker.Lock()
+	for id, chres := range reqs.pending {
+		close(chres)
+		delete(reqs.pending, id)
+	}
+	reqs.locker.Unlock()
+}
+
+// pop remove the pending request by ID and return its response channel.
+func (reqs *wsRequests) pop(id uint64) (chres chan *websocket.Response) {
+	reqs.locker.Lock()
+	chres, ok := reqs.pending[id]
+	if ok {
+		delete(reqs.pending, id)
+	}
+	reqs.locker.Unlock()
+	return chres
+}
+
+func (reqs *wsRequests) push(id uint64) (chres chan *websocket.Response) {
+	chres = make(chan *websocket.Response, 1)
+	reqs.locker.Lock()
+	reqs.pending[id] = chres
+	reqs.locker.Unlock()
+	return chres
+}
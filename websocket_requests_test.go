@@ -0,0 +1,40 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+
+	"github.com/shuLhan/share/lib/test"
+	"github.com/shuLhan/share/lib/websocket"
+)
+
+func TestWSRequests(t *testing.T) {
+	reqs := newWSRequests()
+
+	id1 := reqs.lastID.Add(1)
+	id2 := reqs.lastID.Add(1)
+	test.Assert(t, "unique ID", true, id1 != id2)
+
+	chres1 := reqs.push(id1)
+	chres2 := reqs.push(id2)
+
+	got := reqs.pop(id1)
+	test.Assert(t, "pop", chres1, got)
+	test.Assert(t, "pop again", true, reqs.pop(id1) == nil)
+
+	reqs.failAll()
+
+	_, ok := <-chres2
+	test.Assert(t, "closed on failAll", false, ok)
+	test.Assert(t, "pending", 0, len(reqs.pending))
+
+	// Sending request on unconnected client should return error and
+	// remove the pending request.
+	conn := &websocket.Client{}
+	_, err := reqs.do(conn, 0, "GET", WSPublicSubscription, nil)
+	test.Assert(t, "do error", websocket.ErrConnClosed, err)
+	test.Assert(t, "pending after error", 0, len(reqs.pending))
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+// WebSocketState define the state of WebSocket client connection.
+type WebSocketState int
+
+// List of WebSocket client states.
+const (
+	// WebSocketStateConnecting is the state when client connecting to
+	// server for the first time.
+	WebSocketStateConnecting WebSocketState = iota + 1
+
+	// WebSocketStateConnected is the state when client successfully
+	// connected or reconnected to server.
+	WebSocketStateConnected
+
+	// WebSocketStateDisconnected is the state when client lost the
+	// connection to server unexpectedly.
+	WebSocketStateDisconnected
+
+	// WebSocketStateReconnecting is the state before each reconnect
+	// attempt.
+	WebSocketStateReconnecting
+
+	// WebSocketStateClosed is the state when client closed by user or
+	// stop reconnecting to server.
+	WebSocketStateClosed
+)
+
+// String return the name of state.
+func (state WebSocketState) String() string {
+	switch state {
+	case WebSocketStateConnecting:
+		return "connecting"
+	case WebSocketStateConnected:
+		return "connected"
+	case WebSocketStateDisconnected:
+		return "disconnected"
+	case WebSocketStateReconnecting:
+		return "reconnecting"
+	case WebSocketStateClosed:
+		return "closed"
+	}
+	return "unknown"
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// wsWatchdog detect the stale connection using heartbeat request and the
+// stale topic using the time of last update on each subscribed pair.
+type wsWatchdog struct {
+	opts *WebSocketOptions
+
+	// topics contains the state of each subscribed topic and pair,
+	// indexed by "topic:pair".
+	topics map[string]*topicState
+
+	// lastRecv is the Unix time in nanoseconds when the last frame
+	// received from server.
+	lastRecv atomic.Int64
+
+	locker sync.Mutex
+}
+
+// topicState contains the last update of subscribed pair on topic.
+type topicState struct {
+	last    time.Time
+	topic   string
+	pair    string
+	isStale bool
+}
+
+func newWSWatchdog(opts *WebSocketOptions) (wd *wsWatchdog) {
+	wd = &wsWatchdog{
+		opts:   opts,
+		topics: make(map[string]*topicState),
+	}
+	wd.touch()
+	return wd
+}
+
+// interval return the duration between each check.
+// It return zero if both heartbeat and stale topic detection are disabled.
+func (wd *wsWatchdog) interval() (d time.Duration) {
+	if wd.opts.HeartbeatInterval > 0 {
+		d = wd.opts.HeartbeatInterval
+	}
+	if wd.opts.StaleTimeout > 0 {
+		if d == 0 || wd.opts.StaleTimeout < d {
+			d = wd.opts.StaleTimeout
+		}
+	}
+	return d
+}
+
+// isConnStale return true if there is no frame received from server after
+// heartbeat interval and the heartbeat request is timeout.
+func (wd *wsWatchdog) isConnStale(probe func() error) bool {
+	if wd.opts.HeartbeatInterval <= 0 {
+		return false
+	}
+	if time.Since(wd.lastUpdate()) < wd.opts.HeartbeatInterval {
+		return false
+	}
+
+	err := probe()
+
+	// Any error other than timeout means that we receive response from
+	// server or the connection is already closed.
+	return errors.Is(err, ErrWebSocketTimeout)
+}
+
+// checkTopics mark the subscribed topic that does not receive update
+// after StaleTimeout as stale.
+// It return true if one or more topics become stale.
+func (wd *wsWatchdog) checkTopics() (isStale bool) {
+	if wd.opts.StaleTimeout <= 0 {
+		return false
+	}
+
+	var (
+		now    = time.Now()
+		events []*StaleEvent
+	)
+
+	wd.locker.Lock()
+	for _, st := range wd.topics {
+		if st.isStale || now.Sub(st.last) < wd.opts.StaleTimeout {
+			continue
+		}
+		st.isStale = true
+		events = append(events, &StaleEvent{
+			Time:       now,
+			LastUpdate: st.last,
+			Endpoint:   wd.opts.endpoint,
+			Topic:      st.topic,
+			Pair:       st.pair,
+			IsStale:    true,
+		})
+	}
+	wd.locker.Unlock()
+
+	for _, ev := range events {
+		wd.emit(ev)
+	}
+	return len(events) > 0
+}
+
+func (wd *wsWatchdog) emit(ev *StaleEvent) {
+	if ev.IsStale {
+		log.Printf("%s: stale %s %s since %s", wd.opts.endpoint,
+			ev.Topic, ev.Pair, ev.LastUpdate.Format(time.RFC3339))
+	}
+	if wd.opts.HandleStale != nil {
+		wd.opts.HandleStale(ev)
+	}
+}
+
+func (wd *wsWatchdog) lastUpdate() time.Time {
+	return time.Unix(0, wd.lastRecv.Load())
+}
+
+// reset the time of last update on connection and all topics to current
+// time, for example after the client reconnected.
+// The topic that is stale will keep its status until its receive an update.
+func (wd *wsWatchdog) reset() {
+	wd.touch()
+
+	now := time.Now()
+	wd.locker.Lock()
+	for _, st := range wd.topics {
+		st.last = now
+	}
+	wd.locker.Unlock()
+}
+
+// run check the connection and topics on each interval until the chClosed
+// is closed.
+// The probe function send the heartbeat request to server, while the
+// forceReconnect function close the connection to trigger reconnect.
+func (wd *wsWatchdog) run(
+	probe func() error, isConnected func() bool, forceReconnect func(),
+	chClosed <-chan struct{},
+) {
+	interval := wd.interval()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-chClosed:
+			return
+		case <-ticker.C:
+		}
+
+		if !isConnected() {
+			continue
+		}
+
+		if wd.isConnStale(probe) {
+			wd.emit(&StaleEvent{
+				Time:       time.Now(),
+				LastUpdate: wd.lastUpdate(),
+				Endpoint:   wd.opts.endpoint,
+				IsStale:    true,
+			})
+			forceReconnect()
+			continue
+		}
+
+		if wd.checkTopics() && wd.opts.IsReconnectOnStale {
+			forceReconnect()
+		}
+	}
+}
+
+// touch update the time of last frame received from server.
+func (wd *wsWatchdog) touch() {
+	wd.lastRecv.Store(time.Now().UnixNano())
+}
+
+// touchTopic update the time of last update on topic and pair.
+// If the topic was stale, the recovered event will be emitted.
+func (wd *wsWatchdog) touchTopic(topic, pair string) {
+	var (
+		now = time.Now()
+		ev  *StaleEvent
+	)
+
+	wd.locker.Lock()
+	st := wd.topics[topic+":"+pair]
+	if st != nil {
+		if st.isStale {
+			st.isStale = false
+			ev = &StaleEvent{
+				Time:       now,
+				LastUpdate: st.last,
+				Endpoint:   wd.opts.endpoint,
+				Topic:      topic,
+				Pair:       pair,
+			}
+		}
+		st.last = now
+	}
+	wd.locker.Unlock()
+
+	if ev != nil {
+		wd.emit(ev)
+	}
+}
+
+// track synchronize the watched topics with the current subscription.
+// The pairs that is not subscribed anymore are removed.
+func (wd *wsWatchdog) track(subs *PublicSubscription) {
+	var (
+		now    = time.Now()
+		topics = make(map[string]*topicState)
+	)
+
+	wd.locker.Lock()
+	add := func(topic string, pairs []string) {
+		for _, pair := range pairs {
+			key := topic + ":" + pair
+			st := wd.topics[key]
+			if st == nil {
+				st = &topicState{
+					last:  now,
+					topic: topic,
+					pair:  pair,
+				}
+			}
+			topics[key] = st
+		}
+	}
+	add(TopicDepths, subs.Depths)
+	add(TopicTicker, subs.Ticker)
+	add(TopicTrades, subs.Trades)
+	if subs.Summaries {
+		add(TopicSummaries, []string{""})
+	}
+	wd.topics = topics
+	wd.locker.Unlock()
+}
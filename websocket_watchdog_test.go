@@ -0,0 +1,80 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestWSWatchdog_checkTopics(t *testing.T) {
+	var (
+		events []*StaleEvent
+		opts   = &WebSocketOptions{
+			StaleTimeout: 10 * time.Millisecond,
+			HandleStale: func(ev *StaleEvent) {
+				events = append(events, ev)
+			},
+		}
+	)
+	opts = opts.init(WSPublic)
+
+	wd := newWSWatchdog(opts)
+	wd.track(&PublicSubscription{
+		Depths: []string{PairBitcoinTether},
+		Trades: []string{PairBitcoinTether},
+	})
+
+	test.Assert(t, "checkTopics before timeout", false, wd.checkTopics())
+
+	time.Sleep(20 * time.Millisecond)
+	wd.touchTopic(TopicTrades, PairBitcoinTether)
+
+	test.Assert(t, "checkTopics after timeout", true, wd.checkTopics())
+	test.Assert(t, "len(events)", 1, len(events))
+	test.Assert(t, "events[0].Topic", TopicDepths, events[0].Topic)
+	test.Assert(t, "events[0].IsStale", true, events[0].IsStale)
+
+	// The stale topic is not reported twice.
+	test.Assert(t, "checkTopics again", false, wd.checkTopics())
+
+	wd.touchTopic(TopicDepths, PairBitcoinTether)
+	test.Assert(t, "len(events)", 2, len(events))
+	test.Assert(t, "events[1].IsStale", false, events[1].IsStale)
+
+	// Unsubscribed pair is not watched anymore.
+	wd.track(&PublicSubscription{})
+	time.Sleep(20 * time.Millisecond)
+	test.Assert(t, "checkTopics unsubscribed", false, wd.checkTopics())
+}
+
+func TestWSWatchdog_isConnStale(t *testing.T) {
+	opts := &WebSocketOptions{
+		HeartbeatInterval: 10 * time.Millisecond,
+	}
+	opts = opts.init(WSPublic)
+
+	var (
+		wd       = newWSWatchdog(opts)
+		nprobe   int
+		probeErr error
+		probe    = func() error {
+			nprobe++
+			return probeErr
+		}
+	)
+
+	test.Assert(t, "recently received", false, wd.isConnStale(probe))
+	test.Assert(t, "nprobe", 0, nprobe)
+
+	time.Sleep(20 * time.Millisecond)
+	test.Assert(t, "probe success", false, wd.isConnStale(probe))
+
+	probeErr = ErrWebSocketTimeout
+	test.Assert(t, "probe timeout", true, wd.isConnStale(probe))
+	test.Assert(t, "nprobe", 2, nprobe)
+}
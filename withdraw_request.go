@@ -0,0 +1,91 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"net/url"
+
+	"github.com/shuLhan/share/lib/math/big"
+)
+
+// WithdrawRequest contains parameters for withdrawing asset into another
+// address.
+type WithdrawRequest struct {
+	// Amount of asset to be withdrawn, required.
+	Amount *big.Rat
+
+	// RequestID is the unique ID of withdrawal generated by client,
+	// required.
+	// The same ID will be send to Callback URL for verification.
+	RequestID string
+
+	// Asset name to be withdrawn, required.
+	Asset string
+
+	// Network name of the asset, optional.
+	// For example, for withdrawing asset TEN the network value would be
+	// "erc20".
+	Network string
+
+	// Address of destination wallet, required.
+	Address string
+
+	// AddressType define the type of destination address, optional.
+	AddressType string
+
+	// Memo or tag for destination address, optional.
+	Memo string
+}
+
+// Pack the WithdrawRequest object to be send by REST and/or WebSocket
+// client.
+func (wreq *WithdrawRequest) Pack() (
+	params url.Values, wsparams *WebSocketParams, err error,
+) {
+	err = wreq.validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params = url.Values{
+		ParamNameRequestID:   []string{wreq.RequestID},
+		ParamNameAsset:       []string{wreq.Asset},
+		ParamNameNetwork:     []string{wreq.Network},
+		ParamNameAddress:     []string{wreq.Address},
+		ParamNameAddressType: []string{wreq.AddressType},
+		ParamNameMemo:        []string{wreq.Memo},
+		ParamNameAmount:      []string{wreq.Amount.String()},
+	}
+
+	wsparams = &WebSocketParams{
+		Address:     wreq.Address,
+		AddressType: wreq.AddressType,
+		Asset:       wreq.Asset,
+		Memo:        wreq.Memo,
+		Network:     wreq.Network,
+		RequestID:   wreq.RequestID,
+		TradeRequest: TradeRequest{
+			Amount: wreq.Amount,
+		},
+	}
+
+	return params, wsparams, nil
+}
+
+func (wreq *WithdrawRequest) validate() error {
+	if len(wreq.RequestID) == 0 {
+		return ErrInvalidRequestID
+	}
+	if len(wreq.Asset) == 0 {
+		return ErrInvalidAsset
+	}
+	if len(wreq.Address) == 0 {
+		return ErrWalletAddress
+	}
+	if wreq.Amount == nil || wreq.Amount.IsLessOrEqual(0) {
+		return ErrInvalidAmount
+	}
+	return nil
+}
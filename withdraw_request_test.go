@@ -0,0 +1,100 @@
+// Copyright 2025 CAMP Investment Technologies Ltd. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package camp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/shuLhan/share/lib/math/big"
+	"github.com/shuLhan/share/lib/test"
+)
+
+func TestWithdrawRequest_Pack(t *testing.T) {
+	cases := []struct {
+		desc        string
+		wreq        WithdrawRequest
+		expErr      error
+		expParams   url.Values
+		expWsparams *WebSocketParams
+	}{{
+		desc: "empty request ID",
+		wreq: WithdrawRequest{
+			Asset:   AssetNameBitcoin,
+			Address: "addr",
+			Amount:  big.NewRat(1),
+		},
+		expErr: ErrInvalidRequestID,
+	}, {
+		desc: "empty asset",
+		wreq: WithdrawRequest{
+			RequestID: "1",
+			Address:   "addr",
+			Amount:    big.NewRat(1),
+		},
+		expErr: ErrInvalidAsset,
+	}, {
+		desc: "empty address",
+		wreq: WithdrawRequest{
+			RequestID: "1",
+			Asset:     AssetNameBitcoin,
+			Amount:    big.NewRat(1),
+		},
+		expErr: ErrWalletAddress,
+	}, {
+		desc: "zero amount",
+		wreq: WithdrawRequest{
+			RequestID: "1",
+			Asset:     AssetNameBitcoin,
+			Address:   "addr",
+			Amount:    big.NewRat(0),
+		},
+		expErr: ErrInvalidAmount,
+	}, {
+		desc: "valid",
+		wreq: WithdrawRequest{
+			RequestID:   "1",
+			Asset:       AssetNameTether,
+			Network:     "erc20",
+			Address:     "addr",
+			AddressType: "type",
+			Memo:        "memo",
+			Amount:      big.NewRat("0.5"),
+		},
+		expParams: url.Values{
+			ParamNameRequestID:   []string{"1"},
+			ParamNameAsset:       []string{AssetNameTether},
+			ParamNameNetwork:     []string{"erc20"},
+			ParamNameAddress:     []string{"addr"},
+			ParamNameAddressType: []string{"type"},
+			ParamNameMemo:        []string{"memo"},
+			ParamNameAmount:      []string{"0.5"},
+		},
+		expWsparams: &WebSocketParams{
+			Address:     "addr",
+			AddressType: "type",
+			Asset:       AssetNameTether,
+			Memo:        "memo",
+			Network:     "erc20",
+			RequestID:   "1",
+			TradeRequest: TradeRequest{
+				Amount: big.NewRat("0.5"),
+			},
+		},
+	}}
+
+	for _, c := range cases {
+		t.Log(c.desc)
+
+		params, wsparams, err := c.wreq.Pack()
+		if err != nil {
+			test.Assert(t, "error", c.expErr, err)
+			continue
+		}
+
+		test.Assert(t, "params", c.expParams, params)
+		test.Assert(t, "wsparams", c.expWsparams, wsparams)
+	}
+}